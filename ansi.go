@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ansiCols and ansiRows hold the size of the frames of the ANSI mode along the
+// axes where the board size is not given.
+const ansiCols, ansiRows = 80, 24
+
+// runANSI runs the game without a terminal, writing every generation to w as a
+// frame of ANSI escape sequences, with the cursor moved home between them, so
+// the frames can be piped, recorded or shown by other programs. It runs the
+// generations of -generations, or forever if none, at the speed of -gps.
+func runANSI(opts options, w io.Writer) (err error) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	screen.SetSize(ansiCols, ansiRows)
+	g := newGame(screen, opts)
+	if err := g.openStream(opts.statsJSON); err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := g.closeStream(); err == nil {
+			err = cerr
+		}
+	}()
+	// The frames fit the size of the board given by the flags.
+	cols, rows := ansiCols, ansiRows
+	dw, dh := g.renderer.dots()
+	if !g.fitWidth {
+		cols = (int(g.life.Width())*g.cellWidth() + dw - 1) / dw
+	}
+	if !g.fitHeight {
+		rows = (int(g.life.Height())+dh-1)/dh + 1
+	}
+	if cols < minCols {
+		cols = minCols
+	}
+	if rows < minRows {
+		rows = minRows
+	}
+	screen.SetSize(cols, rows)
+
+	b := bufio.NewWriter(w)
+	b.WriteString("\x1b[2J")
+	for i := uint(0); opts.generations == 0 || i <= opts.generations; i++ {
+		if i > 0 {
+			<-g.tick.C
+			g.step()
+		}
+		g.draw()
+		writeFrame(b, screen)
+		if err := b.Flush(); err != nil {
+			return err
+		}
+		if g.halted() || g.paused {
+			fmt.Fprintln(os.Stderr, g.message)
+			exitCode = int(g.outcome)
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeFrame writes the characters of the screen as ANSI escape sequences,
+// starting at the top left corner of the terminal.
+func writeFrame(b *bufio.Writer, s tcell.Screen) {
+	b.WriteString("\x1b[H")
+	cols, rows := s.Size()
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			b.WriteString("\x1b[0m\n")
+		}
+		var last tcell.Style
+		for x := 0; x < cols; {
+			r, comb, style, width := s.GetContent(x, y)
+			if x == 0 || style != last {
+				b.WriteString(sgr(style))
+				last = style
+			}
+			b.WriteRune(r)
+			for _, c := range comb {
+				b.WriteRune(c)
+			}
+			if width < 1 {
+				width = 1
+			}
+			x += width
+		}
+	}
+	b.WriteString("\x1b[0m")
+}
+
+// sgr returns the escape sequence setting the colors and attributes of style.
+func sgr(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	codes := []string{"0"}
+	for _, a := range []struct {
+		attr tcell.AttrMask
+		code string
+	}{
+		{tcell.AttrBold, "1"},
+		{tcell.AttrDim, "2"},
+		{tcell.AttrItalic, "3"},
+		{tcell.AttrUnderline, "4"},
+		{tcell.AttrBlink, "5"},
+		{tcell.AttrReverse, "7"},
+	} {
+		if attrs&a.attr != 0 {
+			codes = append(codes, a.code)
+		}
+	}
+	if r, g, b := fg.RGB(); r >= 0 {
+		codes = append(codes, "38;2;"+rgbCodes(r, g, b))
+	}
+	if r, g, b := bg.RGB(); r >= 0 {
+		codes = append(codes, "48;2;"+rgbCodes(r, g, b))
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func rgbCodes(r, g, b int32) string {
+	return strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+}
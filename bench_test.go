@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// benchDensities hold the densities of the boards of the benchmarks. The ones
+// of the engine and the parsers are in pkg/life.
+var benchDensities = []float64{0.1, 0.5}
+
+// BenchmarkDraw draws a board filling a terminal of 200x60 characters with
+// the text renderers.
+func BenchmarkDraw(b *testing.B) {
+	for _, r := range []renderer{rendererBraille, rendererHalfBlocks, rendererASCII} {
+		for _, density := range benchDensities {
+			b.Run(fmt.Sprintf("%s/density=%g", r, density), func(b *testing.B) {
+				screen := tcell.NewSimulationScreen("")
+				if err := screen.Init(); err != nil {
+					b.Fatal(err)
+				}
+				defer screen.Fini()
+				screen.SetSize(200, 60)
+				opts := benchOptions(b, "-width", "512", "-height", "256", "-seed", "1",
+					"-density", fmt.Sprint(density), "-renderer", r.String())
+				g := newGame(screen, opts)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					g.draw()
+				}
+			})
+		}
+	}
+}
+
+// benchOptions returns the options of render with the given flags, ignoring
+// the configuration file and the environment.
+func benchOptions(b *testing.B, args ...string) options {
+	fs, finish := optionFlags("render")
+	if err := fs.Parse(args); err != nil {
+		b.Fatal(err)
+	}
+	opts, err := finish(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return opts
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// numBookmarks is the number of bookmarks, chosen with the digits.
+const numBookmarks = 10
+
+// bookmark is a saved state of the board to come back to.
+type bookmark struct {
+	name string
+	snapshot
+}
+
+// bookmarkKey handles the digit after m or ' and reports whether the key was
+// consumed.
+func (g *game) bookmarkKey(event *tcell.EventKey) bool {
+	if g.pending != 'm' && g.pending != '\'' {
+		return false
+	}
+	pending := g.pending
+	g.pending = 0
+	r := event.Rune()
+	if event.Key() != tcell.KeyRune || r < '0' || r > '9' {
+		g.draw()
+		return true
+	}
+	if pending == 'm' {
+		g.mark(int(r-'0'), "")
+	} else {
+		g.jump(int(r - '0'))
+	}
+	return true
+}
+
+// mark saves the current state in a bookmark. Without a name, the bookmark is
+// named after the generation.
+func (g *game) mark(i int, name string) {
+	if name == "" {
+		name = fmt.Sprintf("gen %d", g.epoch)
+	}
+	g.bookmarks[i] = &bookmark{name: name, snapshot: g.snapshot()}
+	g.message = fmt.Sprintf("Bookmark %d: %s", i, name)
+	g.draw()
+}
+
+// jump goes back to the state of a bookmark. The jump can be undone.
+func (g *game) jump(i int) {
+	b := g.bookmarks[i]
+	if b == nil {
+		g.message = fmt.Sprintf("No bookmark %d", i)
+		g.draw()
+		return
+	}
+	g.save()
+	g.message = fmt.Sprintf("Bookmark %d: %s", i, b.name)
+	g.restore(b.snapshot.copy())
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// catagolueSymmetry is the symmetry of the soups of the search, in the notation
+// of Catagolue.
+const catagolueSymmetry = "C1"
+
+// liveCatagolue is the host of the live Catagolue server. The census does not
+// match the one of apgsearch yet, as its soups run on a torus, its objects
+// are split by distance and its pseudo objects are not separated, so its
+// hauls are only submitted to the test servers.
+const liveCatagolue = "catagolue.hatsya.com"
+
+// catagolue holds the options of the soups made and submitted as Catagolue does.
+// See: https://catagolue.hatsya.com/help
+type catagolue struct {
+	// root is the prefix of the ids of the soups, which are made from them
+	// when it is set.
+	root string
+	// submit tells whether the haul is submitted to url with the payosha256
+	// key. The url must be given, and must not be the live server.
+	submit   bool
+	url, key string
+}
+
+// soupID returns the id of the soup of the given number.
+func (cg catagolue) soupID(n uint) string {
+	return cg.root + strconv.FormatUint(uint64(n), 10)
+}
+
+// randomRoot returns a root for the ids of the soups like the ones of apgsearch.
+func randomRoot() string {
+	const chars = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	b := []byte("k_")
+	for i := 0; i < 12; i++ {
+		b = append(b, chars[rng.Intn(len(chars))])
+	}
+	return string(b)
+}
+
+// hashSoup returns the soup of the given id, with the bits of the SHA-256 hash
+// of the id from the top left cell, two bytes for every row.
+func hashSoup(id string) *life.Field {
+	sum := sha256.Sum256([]byte(id))
+	f := life.NewField(soupSize, soupSize)
+	for j, b := range sum {
+		for k := 0; k < 8; k++ {
+			f.Set(uint(k+8*(j%2)), uint(j/2), b&(1<<(7-k)) != 0)
+		}
+	}
+	return f
+}
+
+// check returns an error if the options cannot make soups or submit hauls
+// with the given rule.
+func (cg catagolue) check(rule life.Rule) error {
+	if cg.root == "" && !cg.submit {
+		return nil
+	}
+	if _, err := catagolueRule(rule); err != nil {
+		return err
+	}
+	if !cg.submit {
+		return nil
+	}
+	if cg.url == "" {
+		return errors.New("-submit needs the URL of a test server with -catagolue")
+	}
+	u, err := url.Parse(cg.url)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(u.Hostname(), liveCatagolue) {
+		return fmt.Errorf("the census does not match the one of apgsearch, it is not submitted to %s", liveCatagolue)
+	}
+	return nil
+}
+
+// catagolueRule returns the rule in the notation of Catagolue, like b3s23. The
+// rules with dying states are refused, as the census does not follow their
+// notation.
+func catagolueRule(rule life.Rule) (string, error) {
+	if rule.States > 2 {
+		return "", fmt.Errorf("the census of %s cannot go to Catagolue, it has dying states", rule)
+	}
+	return strings.ToLower(strings.ReplaceAll(rule.String(), "/", "")), nil
+}
+
+// haul returns the census in the format of the hauls of Catagolue, with the id
+// of a soup for every object.
+func (c *census) haul(root string, rule life.Rule, soupID func(n uint) string) (string, error) {
+	r, err := catagolueRule(rule)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "@VERSION go_life-%s\n", Version)
+	fmt.Fprintf(&b, "@MD5 %x\n", md5.Sum([]byte(root)))
+	fmt.Fprintf(&b, "@ROOT %s\n", root)
+	fmt.Fprintf(&b, "@RULE %s\n", r)
+	fmt.Fprintf(&b, "@SYMMETRY %s\n", catagolueSymmetry)
+	fmt.Fprintf(&b, "@NUM_SOUPS %d\n", c.soups)
+	fmt.Fprintf(&b, "@NUM_OBJECTS %d\n", c.objects())
+	b.WriteString("\n@CENSUS TABLE\n")
+	codes := c.codes()
+	for _, code := range codes {
+		fmt.Fprintf(&b, "%s %d\n", code, c.counts[code])
+	}
+	b.WriteString("\n@SAMPLE_SOUPIDS\n")
+	for _, code := range codes {
+		fmt.Fprintf(&b, "%s %s\n", code, soupID(c.samples[code]))
+	}
+	return b.String(), nil
+}
+
+// submitHaul submits the haul of the census, paying for it with the proof of
+// work of payosha256.
+func (cg catagolue) submitHaul(c *census, rule life.Rule) error {
+	if err := cg.check(rule); err != nil {
+		return err
+	}
+	haul, err := c.haul(cg.root, rule, cg.soupID)
+	if err != nil {
+		return err
+	}
+	resp, err := cg.post("/payosha256", "payosha256:get_token:"+cg.key+":post_apgsearch_haul")
+	if err != nil {
+		return err
+	}
+	var target, token string
+	for _, line := range strings.Split(resp, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), ":")
+		if len(parts) == 4 && parts[0] == "payosha256" && parts[1] == "good_token" {
+			target, token = parts[2], parts[3]
+		}
+	}
+	if token == "" {
+		return fmt.Errorf("no payosha256 token from %s: %s", cg.url, strings.TrimSpace(resp))
+	}
+	payment := fmt.Sprintf("payosha256:pay_token:%s:%d\n", token, payToken(token, target))
+	_, err = cg.post("/apgsearch", payment+haul)
+	return err
+}
+
+// payToken returns the first nonce whose SHA-256 hash, after the token, is
+// below the target, both in hexadecimal.
+func payToken(token, target string) int {
+	for nonce := 0; ; nonce++ {
+		sum := sha256.Sum256([]byte(token + ":" + strconv.Itoa(nonce)))
+		if fmt.Sprintf("%x", sum) < target {
+			return nonce
+		}
+	}
+}
+
+// post sends the body to the given path of the Catagolue server and returns the
+// response.
+func (cg catagolue) post(path, body string) (string, error) {
+	resp, err := http.Post(strings.TrimSuffix(cg.url, "/")+path, "text/plain", strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s%s: %s", cg.url, path, resp.Status)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// TestHashSoup checks a soup against the one worked out by hand from the
+// hashsoup of apgsearch: the bits of the SHA-256 hash of the id, from the
+// highest, two bytes for every row.
+func TestHashSoup(t *testing.T) {
+	want, _, err := life.ParsePlaintext([]byte(`.OOOO.....O.O...
+OO.OO.OOOO.OOO..
+O..O.OOO.OOOOO..
+O..OOO.O.O.OO.OO
+..OO..........OO
+OOOOO.OO.O.OOO.O
+O...O.O..OOOO.OO
+O.O.O.O.OOOO..OO
+..O.OO.O.....OO.
+OO.OOO.O..OO.O..
+OO..OO..OO.O..O.
+.OOO...OO.O..O..
+O.O....O...O..OO
+O.....OO..OO..OO
+..O.O....O..OOOO
+..OOOO.OO.OO..O.
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := hashSoup("k_test0")
+	for y := 0; y < soupSize; y++ {
+		for x := 0; x < soupSize; x++ {
+			if got.Alive(x, y) != want.Alive(x, y) {
+				t.Fatalf("cell %d,%d differs", x, y)
+			}
+		}
+	}
+}
+
+func TestCatagolueRule(t *testing.T) {
+	for s, want := range map[string]string{
+		"Life":     "b3s23",
+		"HighLife": "b36s23",
+		"Seeds":    "b2s",
+		"23/36":    "b36s23",
+	} {
+		r, err := life.ParseNamedRule(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := catagolueRule(r); err != nil || got != want {
+			t.Errorf("%s: got %s, %v, want %s", s, got, err, want)
+		}
+	}
+	r, _ := life.ParseNamedRule("Brian's Brain")
+	if got, err := catagolueRule(r); err == nil {
+		t.Errorf("Brian's Brain: got %s, want an error", got)
+	}
+}
+
+func TestCatagolueCheck(t *testing.T) {
+	life3, _ := life.ParseNamedRule("Life")
+	brain, _ := life.ParseNamedRule("Brian's Brain")
+	for _, tc := range []struct {
+		cg   catagolue
+		rule life.Rule
+		ok   bool
+	}{
+		{catagolue{}, brain, true},
+		{catagolue{root: "k_test"}, life3, true},
+		{catagolue{root: "k_test"}, brain, false},
+		{catagolue{submit: true}, life3, false},
+		{catagolue{submit: true, url: "https://catagolue.hatsya.com"}, life3, false},
+		{catagolue{submit: true, url: "https://Catagolue.Hatsya.com:443/"}, life3, false},
+		{catagolue{submit: true, url: "http://localhost:8080"}, life3, true},
+		{catagolue{submit: true, url: "http://localhost:8080"}, brain, false},
+	} {
+		if err := tc.cg.check(tc.rule); (err == nil) != tc.ok {
+			t.Errorf("%+v with %s: got %v", tc.cg, tc.rule, err)
+		}
+	}
+}
+
+func TestHaul(t *testing.T) {
+	c := newCensus()
+	c.add([]string{"xs4_33", "xp2_7", "xs4_33"}, true, 0)
+	c.add([]string{"xs4_33", "xs6_696"}, true, 1)
+	c.add(nil, false, 2)
+	rule, _ := life.ParseNamedRule("Life")
+	cg := catagolue{root: "k_test"}
+	got, err := c.haul(cg.root, rule, cg.soupID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "@VERSION go_life-" + Version + `
+@MD5 9e139b74e847a7bab3d40cb522deebfb
+@ROOT k_test
+@RULE b3s23
+@SYMMETRY C1
+@NUM_SOUPS 3
+@NUM_OBJECTS 5
+
+@CENSUS TABLE
+xs4_33 3
+xp2_7 1
+xs6_696 1
+
+@SAMPLE_SOUPIDS
+xs4_33 k_test0
+xp2_7 k_test0
+xs6_696 k_test1
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
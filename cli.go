@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// subcommand is a subcommand of the command line. It receives the arguments
+// after its name. flags returns its flags, unparsed, for the completion
+// scripts, and the hidden ones are not listed.
+type subcommand struct {
+	help   string
+	run    func(args []string) error
+	flags  func() *flag.FlagSet
+	hidden bool
+}
+
+// subcommands holds the subcommands of the command line by name. Without one,
+// the arguments go to run.
+var subcommands map[string]subcommand
+
+func init() {
+	// Initialized here because help refers to the map itself.
+	subcommands = map[string]subcommand{
+		"run": {"Run the game on the terminal (the default)", func(args []string) error {
+			opts, err := parseArgs("run", args)
+			if err != nil {
+				return err
+			}
+			if err := loadUserPatterns(opts.patterns); err != nil {
+				return err
+			}
+			runFrontend(opts)
+			return nil
+		}, engineFlags("run"), false},
+		"render": {"Run the game without a terminal, writing the final board or every frame to the standard output", func(args []string) error {
+			opts, err := parseArgs("render", args)
+			if err != nil {
+				return err
+			}
+			switch {
+			case opts.video.path != "":
+				return runVideo(opts)
+			case opts.frames.out != "":
+				return runFrames(opts)
+			case opts.format == "ansi":
+				return runANSI(opts, os.Stdout)
+			default:
+				return runHeadless(opts, os.Stdout)
+			}
+		}, engineFlags("render"), false},
+		"convert": {"Convert a pattern file between the RLE and plaintext formats", runConvert, func() *flag.FlagSet {
+			fs, _, _ := convertFlags()
+			return fs
+		}, false},
+		"bench": {"Measure the speed of the engine", func(args []string) error {
+			opts, err := parseArgs("bench", args)
+			if err != nil {
+				return err
+			}
+			return runBench(opts, os.Stdout)
+		}, engineFlags("bench"), false},
+		"search": {"Census the objects left by random soups", func(args []string) error {
+			opts, err := parseArgs("search", args)
+			if err != nil {
+				return err
+			}
+			return runSearch(opts, os.Stdout)
+		}, engineFlags("search"), false},
+		"serve": {"Serve the web build of the game over HTTP", runServe, func() *flag.FlagSet {
+			fs, _, _ := serveFlags()
+			return fs
+		}, false},
+		"help": {"List the subcommands", func(args []string) error {
+			printSubcommands(os.Stdout)
+			return nil
+		}, nil, false},
+		"completion": {"Write the completion script of a shell: bash, zsh or fish", runCompletion, nil, true},
+	}
+}
+
+// engineFlags returns the function returning the flags of the subcommand of the
+// given name running the engine.
+func engineFlags(cmd string) func() *flag.FlagSet {
+	return func() *flag.FlagSet {
+		fs, _ := optionFlags(cmd)
+		return fs
+	}
+}
+
+// runSubcommand runs the subcommand named by the first argument, or run if it
+// names none, so the flags of run can be given on their own.
+func runSubcommand(args []string) error {
+	name := "run"
+	if len(args) > 0 {
+		if _, ok := subcommands[args[0]]; ok {
+			name, args = args[0], args[1:]
+		}
+	}
+	return subcommands[name].run(args)
+}
+
+// printSubcommands writes the list of subcommands.
+func printSubcommands(w io.Writer) {
+	var names []string
+	for name, s := range subcommands {
+		if !s.hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Subcommands:")
+	fmt.Fprintln(w, "")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, subcommands[name].help)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "Run %s SUBCOMMAND -h to list the flags of a subcommand.\n", filepath.Base(os.Args[0]))
+	fmt.Fprintln(w, "")
+}
+
+// newFlagSet returns the flag set of a subcommand with the given arguments.
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintf(fs.Output(), "Usage of %s %s %s:\n", os.Args[0], name, arguments)
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output(), "")
+	}
+	return fs
+}
+
+// runConvert converts the pattern file given in the first argument, writing it
+// to the file given in the second one or to the standard output.
+func runConvert(args []string) error {
+	fs, format, r := convertFlags()
+	if err := presetFlags(fs, "convert"); err != nil {
+		return err
+	}
+	fs.Parse(args)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	field, name, err := readField(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = "rle"
+		if fs.NArg() == 2 && filepath.Ext(fs.Arg(1)) != ".rle" {
+			*format = "plaintext"
+		}
+	}
+	if *format != "rle" && *format != "plaintext" {
+		return fmt.Errorf("invalid format: %s", *format)
+	}
+	var out io.Writer = os.Stdout
+	if fs.NArg() == 2 {
+		f, err := os.Create(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "rle" {
+		return life.WriteRLE(out, name, r.String(), field)
+	}
+	return life.WritePlaintext(out, name, field)
+}
+
+// convertFlags returns the flags of convert: the output format and the rule.
+func convertFlags() (fs *flag.FlagSet, format *string, r *life.Rule) {
+	fs = newFlagSet("convert", "INPUT [OUTPUT]")
+	format = fs.String("format", "", "Output `format` (rle or plaintext). By default, rle if the output ends with .rle and plaintext otherwise, or rle on the standard output")
+	rule, _ := life.ParseNamedRule("B3/S23")
+	r = &rule
+	ruleFlags(fs, r, "`Rule` written to the RLE files, in either notation", "rule")
+	return fs, format, r
+}
+
+// runBench runs the generations of the options on a random soup and writes how
+// fast the engine went.
+func runBench(opts options, w io.Writer) error {
+	l := newLife(opts.rule, opts.width, opts.height)
+	l.Field().Randomize(rand.New(rand.NewSource(time.Now().UnixNano())), opts.density)
+	start := time.Now()
+	for i := uint(0); i < opts.generations; i++ {
+		l.Step()
+	}
+	elapsed := time.Since(start)
+	logs.Info("bench done", "generations", opts.generations, "elapsed", elapsed)
+	cells := float64(opts.width*opts.height) * float64(opts.generations)
+	_, err := fmt.Fprintf(w, "%d generations of %dx%d cells in %v: %.1f gen/s, %.1f Mcells/s\n",
+		opts.generations, opts.width, opts.height, elapsed.Round(time.Millisecond),
+		float64(opts.generations)/elapsed.Seconds(), cells/elapsed.Seconds()/1e6)
+	return err
+}
+
+// runServe serves the directory of the web build, built with make wasm.
+func runServe(args []string) error {
+	fs, addr, dir := serveFlags()
+	if err := presetFlags(fs, "serve"); err != nil {
+		return err
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	if _, err := os.Stat(filepath.Join(*dir, "go_life.wasm")); err != nil {
+		return fmt.Errorf("%s has no web build, run make wasm: %w", *dir, err)
+	}
+	fmt.Printf("Serving %s on http://%s\n", *dir, *addr)
+	err := http.ListenAndServe(*addr, http.FileServer(http.Dir(*dir)))
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveFlags returns the flags of serve: the address and the directory.
+func serveFlags() (fs *flag.FlagSet, addr, dir *string) {
+	fs = newFlagSet("serve", "")
+	addr = fs.String("addr", "localhost:8080", "`Address` to listen on")
+	dir = fs.String("dir", "web", "`Directory` of the web build")
+	return fs, addr, dir
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// TestParseArgsErrors checks that the invalid arguments are returned as errors
+// instead of panicking.
+func TestParseArgsErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-color", "nope"},
+		{"-renderer", "nope"},
+		{"-theme", "nope"},
+		{"-gps", "0"},
+		{"-video-size", "3"},
+		{"-width", "8", "-height", "8", "glider.cells", "extra"},
+		{"-width", "8", "-height", "8", "testdata/missing.cells"},
+	} {
+		if _, err := parseArgs("render", args); err == nil {
+			t.Errorf("%q: no error", args)
+		}
+	}
+	t.Setenv("GO_LIFE_RULE", "B9/S23")
+	if _, err := parseArgs("render", []string{"-width", "8", "-height", "8"}); !errors.Is(err, life.ErrInvalidRule) {
+		t.Errorf("GO_LIFE_RULE: got %v, want an invalid rule", err)
+	}
+}
+
+// TestCompletion checks that every script is written to the given writer and
+// offers the rule names and the embedded patterns, which render takes back.
+func TestCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var b bytes.Buffer
+		if err := writeCompletion(&b, shell); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		for _, word := range []string{"Day_Night", "Brian_s_Brain", "Gosper_glider_gun", "R-pentomino"} {
+			if !strings.Contains(b.String(), word) {
+				t.Errorf("%s: %s not offered", shell, word)
+			}
+		}
+		if shell == "zsh" && !strings.HasPrefix(b.String(), "#compdef "+completionName+"\n") {
+			t.Errorf("zsh: the script starts with %q", strings.SplitN(b.String(), "\n", 2)[0])
+		}
+	}
+	if err := writeCompletion(&bytes.Buffer{}, "nope"); err == nil {
+		t.Error("nope: no error")
+	}
+
+	for _, name := range []string{"Day_Night", "Brian_s_Brain", "day_night"} {
+		if _, err := life.ParseNamedRule(name); err != nil {
+			t.Errorf("-rule %s: %v", name, err)
+		}
+	}
+	opts, err := parseArgs("render", []string{"Gosper_glider_gun"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gun, _ := findPattern("Gosper glider gun")
+	if opts.start.Hash() != gun.Field().Hash() {
+		t.Error("render Gosper_glider_gun does not start from the gun")
+	}
+}
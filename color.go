@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// colorMode tells how the live cells are colored.
+type colorMode int
+
+const (
+	colorNone colorMode = iota
+	colorAge
+	// colorDensity and colorActivity shade the background by the share of
+	// live cells, or of cells changed by the last generation, around every
+	// character.
+	colorDensity
+	colorActivity
+	// colorBirth tells apart the cells born in the last generation from the
+	// ones that survived it, and colorTurnover draws the cells that died in
+	// it too.
+	colorBirth
+	colorTurnover
+	numColorModes
+)
+
+var colorModeNames = [numColorModes]string{"none", "age", "density", "activity", "birth", "turnover"}
+
+func (m colorMode) String() string {
+	return colorModeNames[m]
+}
+
+func parseColorMode(s string) (colorMode, error) {
+	for i, name := range colorModeNames {
+		if name == s {
+			return colorMode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid color mode: %s", s)
+}
+
+// gradient is a sequence of colors evenly spread between 0 and 1.
+type gradient []tcell.Color
+
+// at returns the color of the gradient at t, interpolating the nearest colors.
+func (gr gradient) at(t float64) tcell.Color {
+	if t <= 0 {
+		return gr[0]
+	}
+	if t >= 1 {
+		return gr[len(gr)-1]
+	}
+	pos := t * float64(len(gr)-1)
+	i := int(pos)
+	f := pos - float64(i)
+	r1, g1, b1 := gr[i].RGB()
+	r2, g2, b2 := gr[i+1].RGB()
+	mix := func(a, b int32) int32 {
+		return a + int32(f*float64(b-a))
+	}
+	return tcell.NewRGBColor(mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
+
+// maxAge is the age at which cells reach the end of the age gradient.
+const maxAge = 100
+
+// ageColor returns the color of the live cells of the given age.
+func ageColor(gr gradient, age uint) tcell.Color {
+	return gr.at(math.Log(float64(age)) / math.Log(maxAge))
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// command is a command of the command line. It receives the arguments after
+// its name.
+type command struct {
+	usage string
+	run   func(g *game, args []string) error
+}
+
+// commands holds the commands of the command line by name.
+var commands map[string]command
+
+func init() {
+	// Initialized here because some commands refer to the map itself.
+	commands = map[string]command{
+		"rule": {"rule RULE", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			r, err := life.ParseNamedRule(args[0])
+			if err != nil {
+				return err
+			}
+			g.life.SetRule(r)
+			return nil
+		}},
+		"compare": {"compare RULE|off", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			if args[0] == "off" {
+				g.other = nil
+				g.resize()
+				return nil
+			}
+			r, err := life.ParseNamedRule(args[0])
+			if err != nil {
+				return err
+			}
+			g.compare(r)
+			return nil
+		}},
+		"speed": {"speed GENS_PER_SECOND", func(g *game, args []string) error {
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			if n[0] <= 0 {
+				return errors.New("speed must be positive")
+			}
+			g.setSpeed(n[0])
+			return nil
+		}},
+		"save": {"save FILE", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			return g.saveBoard(args[0])
+		}},
+		"load": {"load FILE [X Y]", func(g *game, args []string) error {
+			if len(args) != 1 && len(args) != 3 {
+				return errUsage
+			}
+			p, err := readPattern(args[0])
+			if err != nil {
+				return err
+			}
+			if len(args) == 3 {
+				n, err := intArgs(args[1:], 2)
+				if err != nil {
+					return err
+				}
+				g.save()
+				p.Stamp(g.life.Field(), n[0], n[1])
+				return nil
+			}
+			g.stamp = p
+			return nil
+		}},
+		"put": {"put PATTERN [X Y]", func(g *game, args []string) error {
+			if len(args) != 1 && len(args) != 3 {
+				return errUsage
+			}
+			lib, ok := libraryPattern(args[0])
+			if !ok {
+				return fmt.Errorf("unknown pattern: %s", args[0])
+			}
+			x, y := g.origin(lib)
+			if len(args) == 3 {
+				n, err := intArgs(args[1:], 2)
+				if err != nil {
+					return err
+				}
+				x, y = n[0], n[1]
+			}
+			g.save()
+			lib.Stamp(g.life.Field(), x, y)
+			return nil
+		}},
+		"seed": {"seed N", func(g *game, args []string) error {
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			g.seed = int64(n[0])
+			g.rng.Seed(g.seed)
+			g.reseed()
+			return nil
+		}},
+		"density": {"density D", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			d, err := strconv.ParseFloat(args[0], 64)
+			if err != nil || d < 0 || d > 1 {
+				return fmt.Errorf("invalid density: %s", args[0])
+			}
+			g.density = d
+			return nil
+		}},
+		"step": {"step [N]", func(g *game, args []string) error {
+			n := []int{1}
+			if len(args) > 0 {
+				var err error
+				if n, err = intArgs(args, 1); err != nil {
+					return err
+				}
+			}
+			g.save()
+			for i := 0; i < n[0]; i++ {
+				g.step()
+			}
+			return nil
+		}},
+		"goto": {"goto GENERATION", func(g *game, args []string) error {
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			if n[0] < 0 {
+				return errors.New("generation must not be negative")
+			}
+			return g.goTo(uint(n[0]))
+		}},
+		"pause": {"pause", func(g *game, args []string) error {
+			g.paused = true
+			return nil
+		}},
+		"run": {"run", func(g *game, args []string) error {
+			g.paused = false
+			return nil
+		}},
+		"clear": {"clear", func(g *game, args []string) error {
+			g.clear()
+			return nil
+		}},
+		"zoom": {"zoom LEVEL", func(g *game, args []string) error {
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			if n[0] < minZoom || n[0] > maxZoom {
+				return fmt.Errorf("zoom must be between %d and %d", minZoom, maxZoom)
+			}
+			g.setZoom(n[0])
+			return nil
+		}},
+		"view": {"view X Y", func(g *game, args []string) error {
+			n, err := intArgs(args, 2)
+			if err != nil {
+				return err
+			}
+			g.viewX, g.viewY = wrap(n[0], int(g.life.Width())), wrap(n[1], int(g.life.Height()))
+			return nil
+		}},
+		"theme": {"theme NAME", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			t, err := findTheme(args[0])
+			if err != nil {
+				return err
+			}
+			g.setTheme(t)
+			return nil
+		}},
+		"palette": {"palette NAME", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			p, err := findPalette(args[0])
+			if err != nil {
+				return err
+			}
+			t, err := findTheme(g.theme.name)
+			if err != nil {
+				return err
+			}
+			g.palette = p
+			g.setTheme(t)
+			return nil
+		}},
+		"renderer": {"renderer NAME", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			r, err := parseRenderer(args[0])
+			if err != nil {
+				return err
+			}
+			if g.mono && r.graphics() {
+				return fmt.Errorf("the %s renderer draws colors", r)
+			}
+			g.setRenderer(r)
+			return nil
+		}},
+		"color": {"color MODE", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			if g.mono {
+				return errors.New("no colors in monochrome mode")
+			}
+			m, err := parseColorMode(args[0])
+			if err != nil {
+				return err
+			}
+			g.colorMode = m
+			return nil
+		}},
+		"grid": {"grid [CHUNK]", func(g *game, args []string) error {
+			if len(args) == 0 {
+				g.showGrid = !g.showGrid
+				return nil
+			}
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			if n[0] <= 0 {
+				return errors.New("chunk must be positive")
+			}
+			g.chunk, g.showGrid = n[0], true
+			return nil
+		}},
+		"trail": {"trail GENERATIONS|off", func(g *game, args []string) error {
+			if len(args) == 1 && args[0] == "off" {
+				return g.setTrail(0)
+			}
+			n, err := intArgs(args, 1)
+			if err != nil {
+				return err
+			}
+			if n[0] <= 0 {
+				return errors.New("trail must be positive")
+			}
+			return g.setTrail(uint(n[0]))
+		}},
+		"mark": {"mark N [NAME]", func(g *game, args []string) error {
+			if len(args) < 1 {
+				return errUsage
+			}
+			i, err := bookmarkArg(args[0])
+			if err != nil {
+				return err
+			}
+			g.mark(i, strings.Join(args[1:], " "))
+			return nil
+		}},
+		"jump": {"jump N", func(g *game, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			i, err := bookmarkArg(args[0])
+			if err != nil {
+				return err
+			}
+			g.jump(i)
+			return nil
+		}},
+		"marks": {"marks", func(g *game, args []string) error {
+			var items []string
+			for i, b := range g.bookmarks {
+				if b != nil {
+					items = append(items, fmt.Sprintf("%d: %s", i, b.name))
+				}
+			}
+			g.message = strings.Join(items, ", ")
+			if len(items) == 0 {
+				g.message = "No bookmarks"
+			}
+			return nil
+		}},
+		"macros": {"macros", func(g *game, args []string) error {
+			g.message = g.macroNames()
+			if g.message == "" {
+				g.message = "No macros"
+			}
+			return nil
+		}},
+		"help": {"help [COMMAND]", func(g *game, args []string) error {
+			if len(args) == 1 {
+				c, ok := commands[args[0]]
+				if !ok {
+					return fmt.Errorf("unknown command: %s", args[0])
+				}
+				g.message = c.usage
+				return nil
+			}
+			var names []string
+			for name := range commands {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			g.message = strings.Join(names, " ")
+			return nil
+		}},
+	}
+}
+
+// errUsage is returned by the commands given wrong arguments.
+var errUsage = errors.New("wrong arguments")
+
+// intArgs parses n integer arguments.
+func intArgs(args []string, n int) ([]int, error) {
+	if len(args) != n {
+		return nil, errUsage
+	}
+	result := make([]int, n)
+	for i, a := range args {
+		v, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", a)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// bookmarkArg parses the number of a bookmark.
+func bookmarkArg(s string) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 || i >= numBookmarks {
+		return 0, fmt.Errorf("invalid bookmark: %s", s)
+	}
+	return i, nil
+}
+
+// execute runs a line of the command line and reports whether it asks to
+// quit. Errors are shown in the status bar.
+func (g *game) execute(line string) (quit bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	name, args := fields[0], fields[1:]
+	if name == "q" || name == "quit" {
+		return true
+	}
+	c, ok := commands[name]
+	if !ok {
+		g.message = "unknown command: " + name
+		return false
+	}
+	if err := c.run(g, args); err == errUsage {
+		g.message = "usage: " + c.usage
+	} else if err != nil {
+		g.message = err.Error()
+	}
+	g.screen.Clear()
+	return false
+}
+
+// saveBoard writes the board to a file, in the RLE format if its extension is
+// .rle or in the plaintext format otherwise.
+func (g *game) saveBoard(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if filepath.Ext(name) == ".rle" {
+		err = life.WriteRLE(f, base, g.life.Rule().String(), g.life.Field())
+	} else {
+		err = life.WritePlaintext(f, base, g.life.Field())
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		g.message = "Saved " + name
+	}
+	return err
+}
+
+// commandKey handles the keys while typing in the command line and reports
+// whether the key was consumed and whether the command asks to quit.
+func (g *game) commandKey(event *tcell.EventKey) (consumed, quit bool) {
+	if !g.prompting {
+		return false, false
+	}
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.prompting = false
+	case tcell.KeyEnter:
+		g.prompting = false
+		quit = g.execute(g.command)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if r := []rune(g.command); len(r) > 0 {
+			g.command = string(r[:len(r)-1])
+		} else {
+			g.prompting = false
+		}
+	case tcell.KeyRune:
+		g.command += string(event.Rune())
+	}
+	g.draw()
+	return true, quit
+}
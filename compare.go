@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// pane is a region of the screen spanning all the rows and the columns from x
+// to x+w. It lets the drawing functions draw the comparison board on the right
+// half of the screen.
+type pane struct {
+	tcell.Screen
+	x, w int
+}
+
+func (p *pane) Size() (int, int) {
+	_, h := p.Screen.Size()
+	return p.w, h
+}
+
+func (p *pane) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	if x >= 0 && x < p.w {
+		p.Screen.SetContent(x+p.x, y, mainc, combc, style)
+	}
+}
+
+func (p *pane) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	if len(ch) > 0 {
+		p.SetContent(x, y, ch[0], ch[1:], style)
+	} else {
+		p.SetContent(x, y, ' ', nil, style)
+	}
+}
+
+func (p *pane) GetContent(x, y int) (rune, []rune, tcell.Style, int) {
+	return p.Screen.GetContent(x+p.x, y)
+}
+
+// compare starts comparing the game board with a copy of it running the given
+// rule.
+func (g *game) compare(r life.Rule) {
+	g.other = newLife(r, g.life.Width(), g.life.Height())
+	g.resize()
+	g.sync()
+}
+
+// sync copies the game board to the comparison board, so both start again from
+// the same state.
+func (g *game) sync() {
+	if g.other == nil {
+		return
+	}
+	g.other.SetField(g.life.Field().Copy())
+	g.screen.Clear()
+	g.draw()
+}
+
+// drawCompare draws the game board on the left half of the screen and the
+// comparison board on the right half.
+func (g *game) drawCompare(cols, rows int) {
+	w, _ := g.boardSize()
+	g.drawBoard(w, rows)
+	for y := 0; y < rows; y++ {
+		g.screen.SetCell(w, y, g.theme.base, '│')
+	}
+	screen, life, stamp := g.screen, g.life, g.stamp
+	g.screen, g.life, g.stamp = &pane{Screen: screen, x: w + 1, w: cols - w - 1}, g.other, nil
+	defer func() { g.screen, g.life, g.stamp = screen, life, stamp }()
+	g.drawBoard(cols-w-1, rows)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// completionName is the name of the program completed by the scripts.
+const completionName = "go_life"
+
+// runCompletion writes the completion script of the shell given in the first
+// argument. The scripts are made from the flags of the subcommands and the
+// values they take, so they keep up with them.
+func runCompletion(args []string) error {
+	fs := newFlagSet("completion", "bash|zsh|fish")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	return writeCompletion(os.Stdout, fs.Arg(0))
+}
+
+// writeCompletion writes the completion script of the given shell to w.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		fmt.Fprintln(w, "#compdef "+completionName)
+		fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+		writeBashCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("invalid shell: %s", shell)
+	}
+	return nil
+}
+
+// completionWord returns the name with its runs of other characters than
+// letters and digits turned into underscores, so "Day & Night" is offered as
+// Day_Night, which the rule flags and the library take back.
+func completionWord(name string) string {
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}), "_")
+}
+
+// patternWords returns the names of the embedded patterns, as completed.
+func patternWords() []string {
+	var words []string
+	for _, p := range library {
+		words = append(words, completionWord(p.Name))
+	}
+	return words
+}
+
+// flagValues returns the values of the flags taking one of a few, by name. The
+// rule flags take the names of the registered rules.
+func flagValues() map[string][]string {
+	values := map[string][]string{
+		"renderer": append(append([]string{}, rendererNames[:]...), "auto"),
+		"color":    colorModeNames[:],
+		"format":   {"rle", "plaintext", "text", "ansi"},
+		"on-stop":  {"pause", "exit"},
+	}
+	for _, t := range themes {
+		values["theme"] = append(values["theme"], t.name)
+	}
+	for _, p := range palettes {
+		values["palette"] = append(values["palette"], p.name)
+	}
+	var rules []string
+	for _, name := range life.RuleNames() {
+		rules = append(rules, completionWord(name))
+	}
+	for _, name := range subcommandNames() {
+		for _, f := range flagsOf(name) {
+			if _, ok := f.Value.(*ruleFlag); ok {
+				values[f.Name] = rules
+			}
+		}
+	}
+	return values
+}
+
+// subcommandNames returns the names of the subcommands, sorted.
+func subcommandNames() []string {
+	var names []string
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flagsOf returns the flags of the subcommand of the given name, if any.
+func flagsOf(name string) []*flag.Flag {
+	s := subcommands[name]
+	if s.flags == nil {
+		return nil
+	}
+	var flags []*flag.Flag
+	s.flags().VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f)
+	})
+	return flags
+}
+
+// isBoolFlag reports whether the flag takes no value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// writeBashCompletion writes the completion script of bash, also read by zsh
+// through bashcompinit.
+func writeBashCompletion(w io.Writer) {
+	names := subcommandNames()
+	fn := "_" + completionName
+	fmt.Fprintf(w, "# bash completion for %s, written by %s completion bash\n", completionName, completionName)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, "\tlocal cur prev sub flags")
+	fmt.Fprintln(w, "\tcur=\"${COMP_WORDS[COMP_CWORD]}\"")
+	fmt.Fprintln(w, "\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"")
+	fmt.Fprintln(w, "\tsub=run")
+	fmt.Fprintln(w, "\tcase \"${COMP_WORDS[1]}\" in")
+	fmt.Fprintf(w, "\t%s) sub=\"${COMP_WORDS[1]}\" ;;\n", strings.Join(names, "|"))
+	fmt.Fprintln(w, "\tesac")
+
+	fmt.Fprintln(w, "\tcase \"$prev\" in")
+	values := flagValues()
+	var valued []string
+	for name := range values {
+		valued = append(valued, name)
+	}
+	sort.Strings(valued)
+	for _, name := range valued {
+		fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(values[name], " "))
+	}
+	fmt.Fprintln(w, "\tesac")
+
+	fmt.Fprintln(w, "\tcase \"$sub\" in")
+	for _, name := range names {
+		var flags []string
+		for _, f := range flagsOf(name) {
+			flags = append(flags, "-"+f.Name)
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(w, "\t%s) flags=%q ;;\n", name, strings.Join(flags, " "))
+		}
+	}
+	fmt.Fprintln(w, "\tesac")
+
+	fmt.Fprintln(w, "\tif [[ $sub == completion && $COMP_CWORD -eq 2 ]]; then")
+	fmt.Fprintln(w, "\t\tCOMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\"))")
+	fmt.Fprintln(w, "\telif [[ $COMP_CWORD -eq 1 && \"$cur\" != -* ]]; then")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintln(w, "\telif [[ \"$cur\" == -* ]]; then")
+	fmt.Fprintln(w, "\t\tCOMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))")
+	// The pattern of render is a file or the name of an embedded one,
+	// unless the previous word is a flag taking the value.
+	var takeValues []string
+	for _, f := range flagsOf("render") {
+		if !isBoolFlag(f) {
+			takeValues = append(takeValues, "-"+f.Name)
+		}
+	}
+	fmt.Fprintf(w, "\telif [[ $sub == render && \" %s \" != *\" $prev \"* ]]; then\n", strings.Join(takeValues, " "))
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\") $(compgen -f -- \"$cur\"))\n", strings.Join(patternWords(), " "))
+	fmt.Fprintln(w, "\tfi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "complete -o default -F %s %s\n", fn, completionName)
+}
+
+// writeFishCompletion writes the completion script of fish.
+func writeFishCompletion(w io.Writer) {
+	names := subcommandNames()
+	values := flagValues()
+	fmt.Fprintf(w, "# fish completion for %s, written by %s completion fish\n", completionName, completionName)
+	for _, name := range names {
+		if s := subcommands[name]; !s.hidden {
+			fmt.Fprintf(w, "complete -c %s -f -n __fish_use_subcommand -a %s -d %s\n", completionName, name, fishQuote(s.help))
+		}
+	}
+	fmt.Fprintf(w, "complete -c %s -f -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n", completionName)
+	fmt.Fprintf(w, "complete -c %s -n '__fish_seen_subcommand_from render' -a %s -d pattern\n", completionName, fishQuote(strings.Join(patternWords(), " ")))
+	for _, name := range names {
+		cond := "__fish_seen_subcommand_from " + name
+		if name == "run" {
+			// The flags of run are taken without naming it too.
+			var others []string
+			for _, other := range names {
+				if other != name {
+					others = append(others, other)
+				}
+			}
+			cond = "not __fish_seen_subcommand_from " + strings.Join(others, " ")
+		}
+		for _, f := range flagsOf(name) {
+			_, usage := flag.UnquoteUsage(f)
+			arg := ""
+			switch {
+			case isBoolFlag(f):
+			case values[f.Name] != nil:
+				arg = " -x -a " + fishQuote(strings.Join(values[f.Name], " "))
+			default:
+				arg = " -r"
+			}
+			fmt.Fprintf(w, "complete -c %s -n %s -o %s%s -d %s\n", completionName, fishQuote(cond), f.Name, arg, fishQuote(usage))
+		}
+	}
+}
+
+// fishQuote quotes s for fish.
+func fishQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+	return "'" + s + "'"
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// config holds the contents of a configuration file. It is written in a subset
+// of TOML: tables, and keys with strings, numbers, booleans or arrays of them.
+type config map[string]map[string]any
+
+// userConfig holds the configuration file, loaded at start.
+var userConfig = config{}
+
+// configPath returns the location of the configuration file.
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go_life", "config.toml"), nil
+}
+
+// loadConfig reads the configuration file. A missing file is not an error.
+func loadConfig() (config, error) {
+	path, err := configPath()
+	if err != nil {
+		return config{}, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := parseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// parseConfig reads a configuration in the TOML subset described by config.
+func parseConfig(r io.Reader) (config, error) {
+	c := config{"": {}}
+	table := ""
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		// Arrays may span several lines.
+		for openBrackets(line) > 0 && strings.Contains(line, "=") && scanner.Scan() {
+			n++
+			line += " " + strings.TrimSpace(stripComment(scanner.Text()))
+		}
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid table: %s", n, line)
+			}
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := c[table]; !ok {
+				c[table] = map[string]any{}
+			}
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key = value: %s", n, line)
+			}
+			v, err := parseValue(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			c[table][unquote(strings.TrimSpace(key))] = v
+		}
+	}
+	return c, scanner.Err()
+}
+
+// outsideQuotes calls fn with the position of every character of s outside the
+// strings, until it returns false. The quotes are left out, and so are the
+// escaped ones inside the basic strings.
+func outsideQuotes(s string, fn func(i int, r rune) bool) {
+	quote, escaped := rune(0), false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && !fn(i, r):
+			return
+		}
+	}
+}
+
+// stripComment removes the comment at the end of a line, if any.
+func stripComment(line string) string {
+	end := len(line)
+	outsideQuotes(line, func(i int, r rune) bool {
+		if r == '#' {
+			end = i
+			return false
+		}
+		return true
+	})
+	return line[:end]
+}
+
+// openBrackets returns the number of brackets of the line left open, outside
+// the strings.
+func openBrackets(line string) int {
+	n := 0
+	outsideQuotes(line, func(_ int, r rune) bool {
+		switch r {
+		case '[':
+			n++
+		case ']':
+			n--
+		}
+		return true
+	})
+	return n
+}
+
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return strings.Trim(s, "'")
+}
+
+// parseValue reads a string, number, boolean or array.
+func parseValue(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "\""):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) > 1:
+		return s[1 : len(s)-1], nil
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("invalid array: %s", s)
+		}
+		var result []any
+		for _, item := range splitItems(s[1 : len(s)-1]) {
+			v, err := parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+		}
+		return result, nil
+	case s == "true" || s == "false":
+		return s == "true", nil
+	}
+	if i, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 0, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid value: %s", s)
+}
+
+// splitItems splits the items of an array at the commas outside strings.
+func splitItems(s string) []string {
+	var items []string
+	start := 0
+	outsideQuotes(s, func(i int, r rune) bool {
+		if r == ',' {
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+		return true
+	})
+	items = append(items, s[start:])
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// tables returns the names of the tables under the given prefix, sorted and
+// without the prefix.
+func (c config) tables(prefix string) []string {
+	var names []string
+	for name := range c {
+		if n, ok := cutPrefix(name, prefix+"."); ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns the string value of a key, reporting whether it exists.
+func (c config) String(table, key string) (string, bool, error) {
+	v, ok := c[table][key]
+	if !ok {
+		return "", false, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", false, fmt.Errorf("%s.%s must be a string", table, key)
+	}
+	return s, true, nil
+}
+
+// Strings returns the value of a key holding an array of strings, reporting
+// whether it exists.
+func (c config) Strings(table, key string) ([]string, bool, error) {
+	v, ok := c[table][key]
+	if !ok {
+		return nil, false, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, false, fmt.Errorf("%s.%s must be an array of strings", table, key)
+	}
+	result := make([]string, len(items))
+	for i, item := range items {
+		if result[i], ok = item.(string); !ok {
+			return nil, false, fmt.Errorf("%s.%s must be an array of strings", table, key)
+		}
+	}
+	return result, true, nil
+}
+
+// applyDefaults sets the flags of the subcommand to the values of the
+// [defaults] table, and then of the [defaults.NAME] table of the subcommand,
+// before the command line overrides them. The keys are the names of the flags,
+// and the ones of [defaults] taken only by other subcommands are skipped.
+func (c config) applyDefaults(fs *flag.FlagSet, name string) error {
+	for _, table := range []string{"defaults", "defaults." + name} {
+		var keys []string
+		for key := range c[table] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if fs.Lookup(key) == nil {
+				if table == "defaults" && anyFlag(key) {
+					continue
+				}
+				return fmt.Errorf("%s.%s: unknown flag", table, key)
+			}
+			if err := fs.Set(key, configFlagValue(c[table][key])); err != nil {
+				return fmt.Errorf("%s.%s: %w", table, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// anyFlag reports whether any subcommand takes the named flag.
+func anyFlag(name string) bool {
+	for _, s := range subcommands {
+		if s.flags != nil && s.flags().Lookup(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// configFlagValue returns a value of the configuration as the value of a flag,
+// with the items of the arrays separated by commas.
+func configFlagValue(v any) string {
+	items, ok := v.([]any)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	s := make([]string, len(items))
+	for i, item := range items {
+		s[i] = fmt.Sprint(item)
+	}
+	return strings.Join(s, ",")
+}
+
+// envPrefix is the prefix of the environment variables setting the flags.
+const envPrefix = "GO_LIFE_"
+
+// envName returns the environment variable of the named flag, like
+// GO_LIFE_MAX_GEN for -max-gen.
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// applyEnv sets the flags of fs to the values of their environment variables.
+func applyEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		v, ok := os.LookupEnv(envName(f.Name))
+		if ok && err == nil {
+			if e := fs.Set(f.Name, v); e != nil {
+				err = fmt.Errorf("%s: %w", envName(f.Name), e)
+			}
+		}
+	})
+	return err
+}
+
+// presetFlags sets the flags of the named subcommand to the values of the
+// configuration file, and then of the environment, before the command line
+// overrides them.
+func presetFlags(fs *flag.FlagSet, name string) error {
+	if err := userConfig.applyDefaults(fs, name); err != nil {
+		return err
+	}
+	startLayer(fs)
+	if err := applyEnv(fs); err != nil {
+		return err
+	}
+	startLayer(fs)
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig(strings.NewReader(`# A comment
+top = 1
+
+[defaults]
+density = 0.25 # after a value
+theme = "dark # not a comment"
+renderer = 'half-blocks'
+escaped = "tab\there \"quoted\""
+paused = true
+generations = 1_000
+rules = [
+	"B3/S23", # Life
+	'B36/S23',
+]
+brackets = ["B3/S23", "a[b"]
+after = 'not in the array'
+closing = [
+	"]", 'b#'
+]
+hash = "a \"#\" b" # the quotes are escaped
+
+[ defaults.render ]
+"quoted key" = -3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		"": {"top": int64(1)},
+		"defaults": {
+			"density":     0.25,
+			"theme":       "dark # not a comment",
+			"renderer":    "half-blocks",
+			"escaped":     "tab\there \"quoted\"",
+			"paused":      true,
+			"generations": int64(1000),
+			"rules":       []any{"B3/S23", "B36/S23"},
+			"brackets":    []any{"B3/S23", "a[b"},
+			"after":       "not in the array",
+			"closing":     []any{"]", "b#"},
+			"hash":        "a \"#\" b",
+		},
+		"defaults.render": {"quoted key": int64(-3)},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %v, want %v", c, want)
+	}
+	for _, s := range []string{
+		"key",
+		"[defaults",
+		"key = nope",
+		"key = \"open",
+		"key = [1, 2",
+		"key = [1, nope]",
+	} {
+		if _, err := parseConfig(strings.NewReader(s)); err == nil {
+			t.Errorf("%q: got no error", s)
+		}
+	}
+}
+
+// TestPresetFlags checks that the flags take the values of the configuration
+// file, then of the subcommand table, then of the environment and then of the
+// command line.
+func TestPresetFlags(t *testing.T) {
+	defer func(c config) { userConfig = c }(userConfig)
+	var err error
+	userConfig, err = parseConfig(strings.NewReader(`[defaults]
+density = 0.1
+generations = 10
+width = 20
+fps = 15
+
+[defaults.render]
+generations = 20
+height = 30
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO_LIFE_HEIGHT", "40")
+	t.Setenv("GO_LIFE_MAX_GEN", "50")
+	fs, _ := optionFlags("render")
+	if err := presetFlags(fs, "render"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-max-gen", "60"}); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		// The file sets the flags of every subcommand, and skips the
+		// ones of other subcommands, like -fps of run.
+		"density": "0.1",
+		"width":   "20",
+		// The table of the subcommand overrides the file.
+		"generations": "20",
+		// The environment overrides both.
+		"height": "40",
+		// The command line overrides everything.
+		"max-gen": "60",
+	} {
+		if got := fs.Lookup(name).Value.String(); got != want {
+			t.Errorf("-%s is %s, want %s", name, got, want)
+		}
+	}
+
+	userConfig = config{"defaults.render": {"nope": int64(1)}}
+	if err := presetFlags(fs, "render"); err == nil {
+		t.Error("an unknown flag of the subcommand table is accepted")
+	}
+	userConfig = config{}
+	t.Setenv("GO_LIFE_WIDTH", "wide")
+	if err := presetFlags(fs, "render"); err == nil || !strings.Contains(err.Error(), "GO_LIFE_WIDTH") {
+		t.Errorf("got %v, want the error of GO_LIFE_WIDTH", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// demo holds a script of -demo: the commands of the command line run at the
+// given times since its start, like
+//
+//	0s   put gosper_glider_gun 10 10
+//	+5s  speed 60
+//	+10s view 40 20
+//	30s  loop
+//
+// The times starting with + count from the previous command, and loop starts
+// the script over.
+type demo struct {
+	actions []demoAction
+	// start is when the script started, and next the position of the next
+	// action to run.
+	start time.Time
+	next  int
+}
+
+// demoAction is a command run at the given time since the start of the demo.
+type demoAction struct {
+	at   time.Duration
+	line string
+}
+
+// readDemo reads the demo script at path. The lines starting with # are
+// comments.
+func readDemo(path string) (*demo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d := &demo{}
+	var last time.Duration
+	scanner := bufio.NewScanner(f)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected a time and a command: %s", path, n, line)
+		}
+		s, relative := cutPrefix(fields[0], "+")
+		at, err := time.ParseDuration(s)
+		if err != nil || at < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid time: %s", path, n, fields[0])
+		}
+		if relative {
+			at += last
+		}
+		if at < last {
+			return nil, fmt.Errorf("%s:%d: the time goes back: %s", path, n, fields[0])
+		}
+		name := fields[1]
+		if _, ok := commands[name]; !ok && name != "loop" && name != "q" && name != "quit" {
+			return nil, fmt.Errorf("%s:%d: unknown command: %s", path, n, name)
+		}
+		d.actions = append(d.actions, demoAction{at, strings.Join(fields[1:], " ")})
+		last = at
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(d.actions) == 0 {
+		return nil, fmt.Errorf("%s: the demo has no commands", path)
+	}
+	return d, nil
+}
+
+// playDemo runs the commands of the demo whose time has come and reports
+// whether one asks to quit. Once the commands are over, the game goes on.
+func (g *game) playDemo() bool {
+	d := g.demo
+	if d.start.IsZero() {
+		d.start = time.Now()
+	}
+	for d.next < len(d.actions) && time.Since(d.start) >= d.actions[d.next].at {
+		line := d.actions[d.next].line
+		d.next++
+		if line == "loop" {
+			d.start, d.next = time.Now(), 0
+			break
+		}
+		logs.Debug("demo", "command", line)
+		if g.execute(line) {
+			return true
+		}
+		g.dirty = true
+	}
+	return false
+}
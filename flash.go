@@ -0,0 +1,47 @@
+package main
+
+import "github.com/kerrigan29a/go_life/pkg/life"
+
+// changes returns the functions reporting whether the cell of the viewport at
+// x, y is born or dies: in the next generation when next is given, or in the
+// last one while flashing. The turnover coloring only takes the deaths of the
+// last generation, since the births have their own color. The functions are
+// nil when no change is highlighted.
+func (g *game) changes(next *life.Field) (born, dies func(x, y int) bool) {
+	switch {
+	case next != nil:
+		born = func(x, y int) bool {
+			p := g.toBoard(x, y)
+			return next.Alive(p.X, p.Y) && !g.alive(x, y)
+		}
+		dies = func(x, y int) bool {
+			p := g.toBoard(x, y)
+			return !next.Alive(p.X, p.Y) && g.alive(x, y)
+		}
+	case g.flashing || g.colorMode == colorTurnover:
+		// The engine keeps the previous generation in its other field.
+		last := g.life.Previous()
+		dies = func(x, y int) bool {
+			p := g.toBoard(x, y)
+			return last.Alive(p.X, p.Y) && !g.alive(x, y)
+		}
+		if g.flashing {
+			born = func(x, y int) bool {
+				p := g.toBoard(x, y)
+				return !last.Alive(p.X, p.Y) && g.alive(x, y)
+			}
+		}
+	}
+	return born, dies
+}
+
+// toggleFlash turns on or off the highlighting of the cells born or dead in
+// the last generation, shown for one frame after every step.
+func (g *game) toggleFlash() {
+	g.flash = !g.flash
+	if g.mono {
+		g.flash = false
+		g.message = "No colors in monochrome mode"
+	}
+	g.draw()
+}
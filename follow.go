@@ -0,0 +1,112 @@
+package main
+
+import "math"
+
+// followMode tells what the viewport tracks.
+type followMode int
+
+const (
+	followOff followMode = iota
+	// followAll keeps the centroid of all the live cells centered.
+	followAll
+	// followObject keeps the centroid of the live cells around a point
+	// centered, moving the point with them.
+	followObject
+)
+
+// followRadius is the distance in cells from the tracked point within which
+// live cells belong to the followed object.
+const followRadius = 8
+
+// toggleFollow starts following the object under the mouse pointer, or under
+// the cursor in the edit mode, or all the live cells if there is no object
+// there. If already following, it stops.
+func (g *game) toggleFollow() {
+	if g.follow != followOff {
+		g.follow = followOff
+		g.message = "Follow off"
+		g.draw()
+		return
+	}
+	x, y := g.cursorX, g.cursorY
+	if !g.editing {
+		cx, cy, cw, ch := g.cellsAt(g.mouseX, g.mouseY)
+		p := g.toBoard(cx+cw/2, cy+ch/2)
+		x, y = p.X, p.Y
+	}
+	if _, _, ok := g.centroidAround(x, y); ok {
+		g.follow, g.followX, g.followY = followObject, x, y
+		g.message = "Follow object"
+	} else {
+		g.follow = followAll
+		g.message = "Follow all"
+	}
+	g.track()
+	g.draw()
+}
+
+// track centers the viewport on what is being followed.
+func (g *game) track() {
+	var x, y int
+	var ok bool
+	switch g.follow {
+	case followOff:
+		return
+	case followAll:
+		x, y, ok = g.centroid()
+	case followObject:
+		if x, y, ok = g.centroidAround(g.followX, g.followY); ok {
+			g.followX, g.followY = x, y
+		} else {
+			g.follow = followOff
+			g.message = "Lost the object"
+		}
+	}
+	if !ok {
+		return
+	}
+	w, h, _, _ := g.cellsAt(g.boardSize())
+	g.viewX, g.viewY = wrap(x-w/2, int(g.life.Width())), wrap(y-h/2, int(g.life.Height()))
+}
+
+// centroid returns the centroid of the live cells, reporting whether there is
+// any. As the board wraps, it is the circular mean of the positions along each
+// axis.
+func (g *game) centroid() (x, y int, ok bool) {
+	w, h := float64(g.life.Width()), float64(g.life.Height())
+	var cx, sx, cy, sy float64
+	g.life.ForEachAlive(func(i, j int) {
+		ok = true
+		cx += math.Cos(2 * math.Pi * float64(i) / w)
+		sx += math.Sin(2 * math.Pi * float64(i) / w)
+		cy += math.Cos(2 * math.Pi * float64(j) / h)
+		sy += math.Sin(2 * math.Pi * float64(j) / h)
+	})
+	if !ok {
+		return 0, 0, false
+	}
+	mean := func(c, s, n float64) int {
+		return wrap(int(math.Round(math.Atan2(s, c)/(2*math.Pi)*n)), int(n))
+	}
+	return mean(cx, sx, w), mean(cy, sy, h), true
+}
+
+// centroidAround returns the centroid of the live cells within followRadius of
+// x, y, reporting whether there is any.
+func (g *game) centroidAround(x, y int) (int, int, bool) {
+	n, sx, sy := 0, 0, 0
+	for dy := -followRadius; dy <= followRadius; dy++ {
+		for dx := -followRadius; dx <= followRadius; dx++ {
+			if g.life.Alive(x+dx, y+dy) {
+				n++
+				sx += dx
+				sy += dy
+			}
+		}
+	}
+	if n == 0 {
+		return 0, 0, false
+	}
+	return wrap(x+int(math.Round(float64(sx)/float64(n))), int(g.life.Width())),
+		wrap(y+int(math.Round(float64(sy)/float64(n))), int(g.life.Height())), true
+}
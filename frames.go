@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// frames holds the options of the images of the generations written by render.
+type frames struct {
+	// out is the pattern of the file names, formatted with the number of the
+	// image, and every the number of generations between the images.
+	out   string
+	every uint
+	// count is the number of images, or 0 to cover -generations.
+	count uint
+}
+
+// fileName returns the file of the image of the given number.
+func (f frames) fileName(n uint) string {
+	if !strings.Contains(f.out, "%") {
+		ext := filepath.Ext(f.out)
+		return fmt.Sprintf("%s%04d%s", strings.TrimSuffix(f.out, ext), n, ext)
+	}
+	return fmt.Sprintf(f.out, n)
+}
+
+// runFrames runs the game without a terminal, writing a PNG image of the board
+// every -every generations to the files of -out, for figures and animations.
+func runFrames(opts options) (err error) {
+	fr := opts.frames
+	g, err := newImageGame(opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := g.closeStream(); err == nil {
+			err = cerr
+		}
+	}()
+	count := fr.count
+	if count == 0 {
+		count = opts.generations/fr.every + 1
+	}
+	for n := uint(0); n < count; n++ {
+		if n > 0 {
+			for i := uint(0); i < fr.every; i++ {
+				g.step()
+			}
+		}
+		if err := writePNG(fr.fileName(n), g); err != nil {
+			return err
+		}
+	}
+	logs.Info("frames done", "images", count, "generations", g.epoch)
+	return nil
+}
+
+// writePNG writes the board of a game of newImageGame to the named file,
+// creating its directory if needed.
+func writePNG(name string, g *game) error {
+	if dir := filepath.Dir(name); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, g.image()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,1512 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// game holds the state of the user interface.
+type game struct {
+	screen tcell.Screen
+	life   *life.Life
+	epoch  uint
+	paused bool
+	// interval is the time between generations.
+	interval time.Duration
+	tick     *time.Ticker
+	// frame triggers the redraw of the screen when dirty is set.
+	frame *time.Ticker
+	dirty bool
+	// dotWidth is the number of braille dots used to draw a cell horizontally.
+	// A value of 2 makes cells look square on most fonts.
+	dotWidth int
+	// zoom is the zoom level. At level 0 every cell is drawn as a braille dot.
+	// Positive levels draw every cell with zoom x zoom characters, so that the
+	// mouse can reach single cells. Negative levels draw 2^-zoom x 2^-zoom
+	// cells with every dot.
+	zoom  int
+	brush brush
+	// stamp is the pattern placed by the next click, if any.
+	stamp *life.Pattern
+	// selected is the index of the last pattern chosen from the library.
+	selected int
+	// mouseX and mouseY hold the last known position of the mouse pointer.
+	mouseX, mouseY int
+	// pointer reports whether the mouse pointer is over the board.
+	pointer bool
+	// measuring is set on the measurement mode.
+	measuring *measure
+	// heatmap is set when the heatmap is shown.
+	heatmap *heatmap
+	// trail is set when the cells dead in the last generations are drawn.
+	trail *trail
+	// showLabels reports whether the objects of the library found on the
+	// board are labeled.
+	showLabels bool
+	// heading is the number of clockwise quarter turns applied to the objects
+	// inserted with the quick keys.
+	heading int
+	// pressed holds the mouse buttons pressed in the last mouse event.
+	pressed tcell.ButtonMask
+	// viewX and viewY hold the board position shown at the top-left corner of
+	// the screen.
+	viewX, viewY int
+	// drag holds the state of the viewport drag in progress, if any.
+	drag *drag
+	// editing reports whether the keyboard moves the cursor at cursorX,
+	// cursorY instead of the viewport.
+	editing          bool
+	cursorX, cursorY int
+	// sel holds the selection in progress, if any.
+	sel *selection
+	// clipboard holds the last copied selection.
+	clipboard *life.Field
+	// message is shown in the status bar until the next key press.
+	message   string
+	colorMode colorMode
+	theme     *theme
+	// palette replaces the colors of the themes.
+	palette *palette
+	// mono reports whether the screen is drawn in black and white, so the
+	// features that rely on colors are off.
+	mono     bool
+	renderer renderer
+	// screensaver is set on the screensaver mode.
+	screensaver *screensaver
+	// halt holds the conditions that stop the game on its own.
+	halt halt
+	// outcome tells why the game last stopped on its own.
+	outcome outcome
+	// recorder is set while the run is recorded, and replay while a replay
+	// is played.
+	recorder *recorder
+	replay   *replay
+	// demo is the demo script being played, if any.
+	demo *demo
+	// stream is set when the statistics of every generation are written.
+	stream *statsStream
+	// showGrid reports whether the grid overlay is drawn, with boundaries
+	// every chunk cells.
+	showGrid bool
+	chunk    int
+	// showHelp reports whether the help overlay is drawn, scrolled down
+	// helpScroll lines.
+	showHelp   bool
+	helpScroll int
+	// picker is the pattern picker, if shown.
+	picker *picker
+	// prompting reports whether the command line is shown, holding command.
+	prompting bool
+	command   string
+	// density is the maximum density of the random soups.
+	density float64
+	// undos and redos hold the states of the board reachable with undo and
+	// redo.
+	undos, redos []snapshot
+	// rewind holds the last generations and forward the ones left behind by
+	// going back through them.
+	rewind  rewind
+	forward []snapshot
+	// bookmarks holds the states saved with m and restored with '.
+	bookmarks [numBookmarks]*bookmark
+	// pending holds the first key of a two key sequence.
+	pending rune
+	// macros holds the keys and the mouse events recorded with K by name,
+	// recording the name of the macro being recorded, if any, and playing
+	// whether one is being played.
+	macros    map[rune][]tcell.Event
+	recording rune
+	playing   bool
+	// populations holds the population of the last generations, shown in the
+	// sparkline when showSpark is set.
+	populations []uint
+	showSpark   bool
+	// stats holds the statistics of the last generations, shown instead of
+	// the board when showStats is set.
+	stats     stats
+	showStats bool
+	// seed is the last seed of the random soups, and rng the generator
+	// seeded with it.
+	seed int64
+	rng  *rand.Rand
+	// follow tells what the viewport tracks. When following an object,
+	// followX and followY hold its board position.
+	follow           followMode
+	followX, followY int
+	// out is the terminal where the images of the graphics renderers are
+	// written, once the characters are shown.
+	out    io.Writer
+	images []placedImage
+	// preview reports whether the next generation is shown while paused.
+	preview bool
+	// flash reports whether the cells born or dead in the last generation are
+	// highlighted, which happens in the first frame after the step, while
+	// flashing is set.
+	flash, flashing bool
+	// other is the comparison board, shown on the right half of the screen
+	// and stepped with the game board.
+	other *life.Life
+	// fitWidth and fitHeight report whether the board follows the terminal
+	// size along each axis.
+	fitWidth, fitHeight bool
+}
+
+// patternMargin is the number of dead cells around the pattern given to render
+// along the axes without a size.
+const patternMargin = 32
+
+// newBoard returns the board of the options with the given size: the pattern
+// of the options in the middle, or a random soup drawn from rng.
+func newBoard(opts options, w, h uint, rng *rand.Rand) *life.Life {
+	l := newLife(opts.rule, w, h)
+	if opts.start == nil {
+		l.Field().Randomize(rng, opts.density)
+		return l
+	}
+	l.Field().Stamp(opts.start, (int(w)-int(opts.start.Width()))/2, (int(h)-int(opts.start.Height()))/2)
+	return l
+}
+
+// newGame returns a game drawn on screen with the given options, seeding the
+// random soups with the seed of the options or with the current time. The
+// caller closes the stream of the statistics, if any.
+func newGame(screen tcell.Screen, opts options) *game {
+	seed := opts.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g := &game{
+		seed:      seed,
+		rng:       rand.New(rand.NewSource(seed)),
+		screen:    screen,
+		interval:  time.Second / time.Duration(opts.gps),
+		dotWidth:  1,
+		fitWidth:  opts.width == 0,
+		fitHeight: opts.height == 0,
+	}
+	if opts.square {
+		g.dotWidth = 2
+	}
+	if opts.mono {
+		g.screen, g.mono = &monoScreen{Screen: screen}, true
+	}
+	g.renderer = opts.renderer
+	g.chunk = int(opts.chunk)
+	g.rewind.budget = int(opts.rewind) << 20
+	g.screensaver = opts.screensaver
+	g.halt = opts.halt
+	g.paused = opts.paused
+	g.replay = opts.replay
+	g.demo = opts.demo
+	w, h := g.fit(opts.width, opts.height)
+	g.life = newBoard(opts, w, h, g.rng)
+	g.life.OnStep(g.stepped)
+	if opts.trail > 0 {
+		g.trail = newTrail(w, h, opts.trail)
+	}
+	g.density = opts.density
+	g.colorMode = opts.colorMode
+	g.palette = opts.palette
+	g.setTheme(opts.theme)
+	if opts.compare != nil {
+		g.compare(*opts.compare)
+	}
+	g.tick = time.NewTicker(g.interval)
+	return g
+}
+
+// fit returns the size of the game board, taking the size of the terminal for
+// the axes that follow it.
+func (g *game) fit(w, h uint) (uint, uint) {
+	if g.tooSmall() && g.life != nil {
+		// The board keeps its size until the terminal grows again.
+		return g.life.Width(), g.life.Height()
+	}
+	cols, rows := g.boardSize()
+	if g.tooSmall() {
+		cols, rows = minCols, minRows-1
+	}
+	dw, dh := g.renderer.dots()
+	if g.fitWidth {
+		w = uint(cols * dw / g.cellWidth())
+	}
+	if g.fitHeight {
+		h = uint(rows * dh)
+	}
+	return w, h
+}
+
+// suspend gives the terminal back to the shell and stops the process, like
+// Ctrl+Z does, taking the terminal again when the shell continues it.
+func (g *game) suspend() {
+	if g.renderer.graphics() {
+		g.clearImages()
+	}
+	if err := g.screen.Suspend(); err != nil {
+		panic(err)
+	}
+	stopped := stop()
+	if err := g.screen.Resume(); err != nil {
+		panic(err)
+	}
+	if !stopped {
+		g.message = "Suspend not supported"
+	}
+	// The terminal may have changed while stopped.
+	g.resize()
+}
+
+// resize adapts the game board to a new terminal size.
+func (g *game) resize() {
+	if w, h := g.fit(g.life.Width(), g.life.Height()); w != g.life.Width() || h != g.life.Height() {
+		g.life.Resize(w, h, life.TopLeft)
+	}
+	if g.other != nil && (g.other.Width() != g.life.Width() || g.other.Height() != g.life.Height()) {
+		g.other.Resize(g.life.Width(), g.life.Height(), life.TopLeft)
+	}
+	g.viewX, g.viewY = wrap(g.viewX, int(g.life.Width())), wrap(g.viewY, int(g.life.Height()))
+	g.screen.Clear()
+	g.draw()
+	g.screen.Sync()
+}
+
+// minCols and minRows are the size of the smallest terminal the game is drawn
+// on. Smaller terminals show a message until they grow.
+const minCols, minRows = 20, 5
+
+// tooSmall reports whether the terminal is smaller than minCols by minRows.
+func (g *game) tooSmall() bool {
+	cols, rows := g.screen.Size()
+	return cols < minCols || rows < minRows
+}
+
+// drawTooSmall replaces the game with a message asking for a larger terminal.
+func (g *game) drawTooSmall() {
+	if g.renderer.graphics() {
+		g.clearImages()
+	}
+	g.screen.Clear()
+	cols, rows := g.screen.Size()
+	text := fmt.Sprintf("Terminal too small, needs %dx%d", minCols, minRows)
+	if len(text) > cols {
+		text = "Too small"
+	}
+	x := (cols - len(text)) / 2
+	if x < 0 {
+		x = 0
+	}
+	g.drawText(x, rows/2, cols, g.theme.status, text)
+	g.screen.Show()
+	g.dirty = false
+}
+
+// size returns the number of columns and rows of the screen available to draw
+// the game board. The last row is reserved for the status bar.
+func (g *game) size() (cols, rows int) {
+	cols, rows = g.screen.Size()
+	if rows > 0 {
+		rows--
+	}
+	return cols, rows
+}
+
+// boardSize returns the number of columns and rows of the screen showing the
+// game board. When comparing rules, the board takes the left half.
+func (g *game) boardSize() (cols, rows int) {
+	cols, rows = g.size()
+	if g.other != nil {
+		cols = (cols - 1) / 2
+	}
+	return cols, rows
+}
+
+func (g *game) draw() {
+	if g.tooSmall() {
+		g.drawTooSmall()
+		return
+	}
+	cols, rows := g.size()
+	g.images = g.images[:0]
+	if g.showStats {
+		g.drawStats(cols, rows)
+		g.drawStatus(cols, rows)
+		g.screen.Show()
+		g.dirty = false
+		return
+	}
+	if g.other != nil {
+		g.drawCompare(cols, rows)
+	} else {
+		g.drawBoard(cols, rows)
+	}
+	// The selection and the cursor stay on the game board.
+	boardCols, _ := g.boardSize()
+	if g.sel != nil && g.sel.anchor {
+		g.drawSelection(boardCols, rows)
+	}
+	if g.editing {
+		g.drawCursor(boardCols, rows)
+	}
+	if g.showLabels {
+		g.drawLabels(boardCols, rows)
+	}
+	if g.measuring != nil {
+		g.drawMeasure(boardCols, rows)
+	}
+	if g.showSpark {
+		g.drawSparkline(cols, rows)
+	}
+	if g.showTimeline() {
+		g.drawTimeline(boardCols, rows)
+	}
+	if g.picker != nil {
+		g.drawPicker(cols, rows)
+	}
+	if g.showHelp {
+		g.drawHelp(cols, rows)
+	}
+	g.drawStatus(cols, rows)
+	g.screen.Show()
+	// The images would hide the overlays drawn with characters.
+	if g.picker == nil && !g.showHelp {
+		g.writeImages()
+	}
+	// The next frame draws the board without the flash.
+	g.dirty = g.flashing
+	g.flashing = false
+}
+
+// drawBoard draws the cells of the viewport and the grid.
+func (g *game) drawBoard(cols, rows int) {
+	var next *life.Field
+	if g.preview && g.paused {
+		next = g.life.Peek()
+	}
+	switch {
+	case g.renderer.graphics():
+		g.drawImage(cols, rows, next)
+	case g.zoom > 0:
+		g.drawBlocks(cols, rows, next)
+	default:
+		g.drawDots(cols, rows, next)
+	}
+	if g.showGrid {
+		g.drawGrid(cols, rows)
+	}
+}
+
+// drawStatus draws the status bar in the given row, or the command line while
+// typing a command.
+func (g *game) drawStatus(cols, row int) {
+	if g.prompting {
+		g.drawText(0, row, cols, g.theme.status, ":"+g.command+"_")
+		return
+	}
+	state := "running"
+	if g.paused {
+		state = "paused"
+	}
+	text := fmt.Sprintf(" Gen %d | Pop %d | %s | %s | %.4g gen/s",
+		g.epoch, g.life.Population(), g.life.Rule(), state, float64(time.Second)/float64(g.interval))
+	if g.zoom > 0 {
+		text += fmt.Sprintf(" | Zoom %dx", g.zoom)
+	} else if g.zoom < 0 {
+		text += fmt.Sprintf(" | Zoom 1/%d", g.cellsPerDot())
+	}
+	text += fmt.Sprintf(" | View %d,%d", g.viewX, g.viewY)
+	switch g.follow {
+	case followAll:
+		text += " | Follow all"
+	case followObject:
+		text += " | Follow object"
+	}
+	if g.other != nil {
+		text += fmt.Sprintf(" | vs %s Pop %d", g.other.Rule(), g.other.Population())
+	}
+	if g.showGrid {
+		text += fmt.Sprintf(" | Grid %d", g.chunk)
+	}
+	if g.colorMode != colorNone {
+		text += fmt.Sprintf(" | Color %s", g.colorMode)
+	}
+	if g.heatmap != nil {
+		text += " | Heatmap"
+	}
+	if g.editing {
+		text += fmt.Sprintf(" | Edit %d,%d", g.cursorX, g.cursorY)
+	} else if g.pointer {
+		text += " | " + g.pointerCells()
+	}
+	if g.sel != nil {
+		text += " | Select"
+	}
+	if m := g.measuring; m != nil && m.points == 2 {
+		text += " | Measure " + g.measureText()
+	} else if m != nil {
+		text += " | Measure"
+	}
+	if g.message != "" {
+		text += " | " + g.message
+	}
+	g.drawText(0, row, cols, g.theme.status, text)
+}
+
+// drawText draws text in the given row, starting at column x and filling with
+// spaces until column cols.
+func (g *game) drawText(x, row, cols int, style tcell.Style, text string) {
+	for _, r := range text {
+		if x >= cols {
+			return
+		}
+		g.screen.SetCell(x, row, style, r)
+		x++
+	}
+	for ; x < cols; x++ {
+		g.screen.SetCell(x, row, style, ' ')
+	}
+}
+
+// alive reports whether the cell at the given position of the viewport is
+// alive.
+func (g *game) alive(x, y int) bool {
+	return g.life.Alive(x+g.viewX, y+g.viewY)
+}
+
+// ghost returns the board positions covered by the live cells of the stamp.
+func (g *game) ghost() map[image.Point]bool {
+	if g.stamp == nil {
+		return nil
+	}
+	ox, oy := g.origin(g.stamp)
+	result := make(map[image.Point]bool)
+	for _, c := range g.stamp.Cells {
+		result[image.Pt(wrap(ox+c.X, int(g.life.Width())), wrap(oy+c.Y, int(g.life.Height())))] = true
+	}
+	return result
+}
+
+// cellWidth returns the number of dots used to draw a cell horizontally, which
+// is two at least for the renderers with characters two columns wide.
+func (g *game) cellWidth() int {
+	if span := g.renderer.span(); span > g.dotWidth {
+		return span
+	}
+	return g.dotWidth
+}
+
+// dotRegion returns the region of cells drawn by the dot at px, py when zoomed
+// out. The region is at least one cell wide.
+func (g *game) dotRegion(px, py int) (x0, y0, x1, y1 int) {
+	f := g.cellsPerDot()
+	x0, x1 = px*f/g.cellWidth(), (px+1)*f/g.cellWidth()
+	if x1 == x0 {
+		x1 = x0 + 1
+	}
+	return x0, py * f, x1, (py + 1) * f
+}
+
+// anyIn reports whether fn is true for any cell of the region inside the board.
+func (g *game) anyIn(x0, y0, x1, y1 int, fn func(x, y int) bool) bool {
+	for y := y0; y < y1 && y < int(g.life.Height()); y++ {
+		for x := x0; x < x1 && x < int(g.life.Width()); x++ {
+			if fn(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// youngest returns the smallest age of the live cells of the region inside the
+// board, or 0 if all of them are dead.
+func (g *game) youngest(x0, y0, x1, y1 int) uint {
+	min := uint(0)
+	for y := y0; y < y1 && y < int(g.life.Height()); y++ {
+		for x := x0; x < x1 && x < int(g.life.Width()); x++ {
+			if a := g.life.Age(x+g.viewX, y+g.viewY); a > 0 && (min == 0 || a < min) {
+				min = a
+			}
+		}
+	}
+	return min
+}
+
+// cellStyle returns the style for a character whose youngest live cell has the
+// given age.
+func (g *game) cellStyle(age uint) tcell.Style {
+	if g.colorMode == colorAge && age > 0 {
+		return g.theme.base.Foreground(ageColor(g.theme.age, age))
+	}
+	if (g.colorMode == colorBirth || g.colorMode == colorTurnover) && age == 1 {
+		return g.theme.base.Foreground(g.theme.birth)
+	}
+	return g.theme.base
+}
+
+// heatMargin is the number of cells around a character also counted to shade
+// it.
+const heatMargin = 1
+
+// shade returns style with the background shaded by the density or activity of
+// the cells around the given region of the viewport, depending on the coloring
+// mode, or by the changes counted by the heatmap when it is shown.
+func (g *game) shade(style tcell.Style, x0, y0, x1, y1 int) tcell.Style {
+	// weight returns the share of a cell, from 0 to most.
+	var weight func(x, y int) int
+	most := 1
+	switch {
+	case g.heatmap != nil && (g.heatmap.w != g.life.Width() || g.heatmap.h != g.life.Height()):
+		// The counts start again with the next generation.
+		return style
+	case g.heatmap != nil:
+		weight = func(x, y int) int {
+			p := g.toBoard(x, y)
+			return int(g.heatmap.counts[p.Y][p.X])
+		}
+		most = heatWindow
+	case g.colorMode == colorDensity:
+		weight = func(x, y int) int {
+			if g.alive(x, y) {
+				return 1
+			}
+			return 0
+		}
+	case g.colorMode == colorActivity:
+		weight = func(x, y int) int {
+			if g.life.Changed(x+g.viewX, y+g.viewY) {
+				return 1
+			}
+			return 0
+		}
+	default:
+		return style
+	}
+	n, total := 0, 0
+	for y := y0 - heatMargin; y < y1+heatMargin; y++ {
+		for x := x0 - heatMargin; x < x1+heatMargin; x++ {
+			total += most
+			n += weight(x, y)
+		}
+	}
+	if n == 0 {
+		return style
+	}
+	// Densities over one half are rare, so they take the whole gradient.
+	return style.Background(g.theme.heat.at(2 * float64(n) / float64(total)))
+}
+
+// drawDots draws the viewport using the dots of the characters of the
+// renderer. With next, or while flashing, the cells born or dead are drawn too,
+// and the characters with births or deaths are highlighted.
+func (g *game) drawDots(cols, rows int, next *life.Field) {
+	ghost := g.ghost()
+	inGhost := func(x, y int) bool { return ghost[g.toBoard(x, y)] }
+	born, dies := g.changes(next)
+	dw, dh := g.renderer.dots()
+	// The dots of all the columns of a character make up its glyph.
+	span := g.renderer.span()
+	cw := dw * span
+	// ages holds the age of the youngest live cell of every dot.
+	ages := make([]uint, cw*dh)
+	for y := 0; y < rows; y++ {
+		for x := 0; x+span <= cols; x += span {
+			if x0, y0, _, _ := g.dotRegion(x*dw, y*dh); x0 >= int(g.life.Width()) || y0 >= int(g.life.Height()) {
+				break
+			}
+			var mask uint
+			ghosted, births, deaths := false, false, false
+			// faded holds the most recent death of the trails of the dots.
+			live, faded := false, uint(0)
+			for dy := 0; dy < dh; dy++ {
+				for dx := 0; dx < cw; dx++ {
+					i := dy*cw + dx
+					ages[i] = 0
+					x0, y0, x1, y1 := g.dotRegion(x*dw+dx, y*dh+dy)
+					if x0 >= int(g.life.Width()) || y0 >= int(g.life.Height()) {
+						continue
+					}
+					if g.anyIn(x0, y0, x1, y1, inGhost) {
+						ghosted = true
+						mask |= 1 << i
+						continue
+					}
+					if ages[i] = g.youngest(x0, y0, x1, y1); ages[i] > 0 {
+						live = true
+						mask |= 1 << i
+					} else if f := g.fading(x0, y0, x1, y1); f > 0 {
+						if faded == 0 || f < faded {
+							faded = f
+						}
+						mask |= 1 << i
+					}
+					if born != nil && g.anyIn(x0, y0, x1, y1, born) {
+						births = true
+						mask |= 1 << i
+					}
+					// The cells that just died are drawn too.
+					if dies != nil && !births && g.anyIn(x0, y0, x1, y1, dies) {
+						deaths = true
+						mask |= 1 << i
+					}
+				}
+			}
+			style := g.dotsStyle(mask, ghosted, ages)
+			if !ghosted && !live && faded > 0 {
+				style = g.trailStyle(faded)
+			}
+			x0, y0, _, _ := g.dotRegion(x*dw, y*dh)
+			_, _, x1, y1 := g.dotRegion(x*dw+cw-1, y*dh+dh-1)
+			style = g.shade(style, x0, y0, x1, y1)
+			if !ghosted && births {
+				style = g.theme.base.Foreground(g.theme.birth).Dim(true)
+			} else if !ghosted && deaths {
+				style = g.theme.base.Foreground(g.theme.death).Dim(true)
+			}
+			g.screen.SetCell(x, y, style, g.renderer.glyph(mask))
+		}
+	}
+}
+
+// dotsStyle returns the style for a character with the given dots and ages.
+func (g *game) dotsStyle(mask uint, ghosted bool, ages []uint) tcell.Style {
+	if ghosted {
+		return g.theme.base.Foreground(g.theme.ghost)
+	}
+	// Half blocks color every half on its own, using the background for the
+	// lower one.
+	if g.renderer == rendererHalfBlocks && g.colorMode == colorAge && mask == 0b11 {
+		return g.theme.base.
+			Foreground(ageColor(g.theme.age, ages[0])).
+			Background(ageColor(g.theme.age, ages[1]))
+	}
+	min := uint(0)
+	for _, a := range ages {
+		if a > 0 && (min == 0 || a < min) {
+			min = a
+		}
+	}
+	return g.cellStyle(min)
+}
+
+// drawBlocks draws the viewport using one or more whole characters for every
+// cell. With next, or while flashing, the cells born or dying are highlighted.
+func (g *game) drawBlocks(cols, rows int, next *life.Field) {
+	ghost := g.ghost()
+	born, dies := g.changes(next)
+	cw, ch := g.zoom*g.cellWidth(), g.zoom
+	// The characters two columns wide cover the next column too.
+	span := g.renderer.span()
+	for y := 0; y < rows && y/ch < int(g.life.Height()); y++ {
+		for x := 0; x+span <= cols && x/cw < int(g.life.Width()); x += span {
+			age := g.life.Age(x/cw+g.viewX, y/ch+g.viewY)
+			r := g.renderer.block(age > 0)
+			style := g.cellStyle(age)
+			if f := g.fading(x/cw, y/ch, x/cw+1, y/ch+1); age == 0 && f > 0 {
+				r, style = g.renderer.block(true), g.trailStyle(f)
+			}
+			style = g.shade(style, x/cw, y/ch, x/cw+1, y/ch+1)
+			switch {
+			case ghost[g.toBoard(x/cw, y/ch)]:
+				r, style = g.renderer.block(true), g.theme.base.Foreground(g.theme.ghost)
+			case born != nil && born(x/cw, y/ch):
+				r, style = g.renderer.block(true), g.theme.base.Foreground(g.theme.birth).Dim(true)
+			case dies != nil && dies(x/cw, y/ch):
+				r, style = g.renderer.block(true), g.theme.base.Foreground(g.theme.death).Dim(true)
+			}
+			g.screen.SetCell(x, y, style, r)
+		}
+	}
+}
+
+// step advances the game by one generation, leaving the drawing for the next
+// frame.
+func (g *game) step() {
+	g.rewind.push(g.boards())
+	g.forward = nil
+	g.epoch++
+	start := time.Now()
+	g.life.Step()
+	elapsed := time.Since(start)
+	g.stats.timed(elapsed)
+	logs.Debug("step", "generation", g.epoch, "population", g.life.Population(), "elapsed", elapsed)
+	if g.other != nil {
+		g.other.Step()
+	}
+	if g.heatmap != nil {
+		g.heatmap.add(g.life)
+	}
+	if g.trail != nil {
+		g.trail.add(g.life)
+	}
+	g.record()
+	g.track()
+	g.flashing = g.flash
+	g.dirty = true
+	g.restart()
+}
+
+// openStream opens the stream of the statistics of every generation at path,
+// if any. The caller closes it with closeStream.
+func (g *game) openStream(path string) (err error) {
+	if path != "" {
+		g.stream, err = openStatsStream(path)
+	}
+	return err
+}
+
+// closeStream closes the stream of the statistics, if any, returning the
+// first error writing them.
+func (g *game) closeStream() error {
+	if g.stream == nil {
+		return nil
+	}
+	return g.stream.Close()
+}
+
+// stepped saves the changes of a step of the board, called by the board after
+// every step. The epoch is already the one of the new generation.
+func (g *game) stepped(_ uint64, st life.StepStats) {
+	g.stats.add(st)
+	if g.stream != nil {
+		g.stream.write(g.life, g.epoch, st)
+	}
+}
+
+func (g *game) next() {
+	g.step()
+	g.draw()
+}
+
+// key handles a key press and reports whether the program must exit.
+func (g *game) key(event *tcell.EventKey) bool {
+	g.recordMacro(event)
+	if consumed, quit := g.commandKey(event); consumed {
+		return quit
+	}
+	if g.helpKey(event) || g.pickerKey(event) || g.bookmarkKey(event) || g.macroKey(event) {
+		return false
+	}
+	if g.stamp != nil && g.stampKey(event) {
+		return false
+	}
+	if g.measuring != nil && g.measureKey(event) {
+		return false
+	}
+	g.message = ""
+	if g.sel != nil && g.selectionKey(event) {
+		return false
+	}
+	if g.editing && g.editKey(event) {
+		return false
+	}
+	if event = bindKey(event); event == nil {
+		return false
+	}
+	switch event.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyCtrlZ:
+		g.suspend()
+	case tcell.KeyCtrlV:
+		g.paste()
+	case tcell.KeyCtrlR:
+		g.redo()
+	case tcell.KeyDelete:
+		g.clear()
+	case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight:
+		dx, dy := direction(event.Key())
+		if event.Modifiers()&tcell.ModShift != 0 {
+			g.save()
+			g.life.Field().Shift(dx, dy)
+			g.draw()
+		} else {
+			g.move(dx, dy)
+		}
+	case tcell.KeyRune:
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			if r := event.Rune(); r >= '1' && r <= '9' {
+				g.setSpeed(speedPresets[r-'1'])
+			}
+			return false
+		}
+		switch event.Rune() {
+		case 'q', 'Q':
+			return true
+		case 'p', 'P':
+			g.paused = !g.paused
+			g.draw()
+		case '+':
+			g.setInterval(g.interval / 2)
+		case '-':
+			g.setInterval(g.interval * 2)
+		case 'c':
+			g.screen.Sync()
+		case 'T':
+			g.setTheme(themes[(g.themeIndex()+1)%len(themes)])
+			g.message = "Theme " + g.theme.name
+			g.draw()
+		case 'G':
+			r := (g.renderer + 1) % numRenderers
+			if g.mono && r.graphics() {
+				r = 0
+			}
+			g.setRenderer(r)
+		case '=':
+			g.sync()
+		case '@':
+			g.toggleFollow()
+		case 'S':
+			g.showSpark = !g.showSpark
+			g.screen.Clear()
+			g.draw()
+		case 'I':
+			g.toggleStats()
+		case 'a':
+			g.preview = !g.preview
+			g.draw()
+		case 'A':
+			g.toggleFlash()
+		case 'm', '\'', '&':
+			g.pending = event.Rune()
+		case 'K':
+			if g.recording != 0 {
+				g.stopMacro()
+			} else if !g.playing {
+				g.pending = 'K'
+			}
+		case ':':
+			g.prompting, g.command = true, ""
+			g.draw()
+		case 'i':
+			g.openPicker()
+		case 'M':
+			g.startMeasure()
+		case 'H':
+			g.toggleHeatmap()
+		case 'L':
+			g.toggleLabels()
+		case '?':
+			g.showHelp, g.helpScroll = true, 0
+			g.draw()
+		case '#':
+			g.showGrid = !g.showGrid
+			g.draw()
+		case 'C':
+			if g.mono {
+				g.message = "No colors in monochrome mode"
+			} else {
+				g.colorMode = (g.colorMode + 1) % numColorModes
+			}
+			g.draw()
+		case 'n', 'N':
+			if g.paused {
+				g.save()
+				g.next()
+			}
+		case ',':
+			if g.paused {
+				g.back()
+			}
+		case '.':
+			if g.paused {
+				g.forth()
+			}
+		case 'r':
+			// The rotated board keeps its size instead of being cropped
+			// back to the terminal.
+			g.fitWidth, g.fitHeight = false, false
+			g.save()
+			g.life.Rotate()
+			if g.other != nil {
+				g.other.Rotate()
+			}
+			g.resize()
+		case 'f':
+			g.save()
+			g.life.Field().FlipHorizontal()
+			g.draw()
+		case 'F':
+			g.save()
+			g.life.Field().FlipVertical()
+			g.draw()
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			g.brush.size = int(event.Rune() - '0')
+		case 't':
+			g.brush.round = !g.brush.round
+		case 'h':
+			g.move(-1, 0)
+		case 'j':
+			g.move(0, 1)
+		case 'k':
+			g.move(0, -1)
+		case 'l':
+			g.move(1, 0)
+		case 'R':
+			g.reseed()
+		case 'x':
+			g.clear()
+		case 'D':
+			g.density = nextDensity(g.density)
+			g.message = fmt.Sprintf("Density %.0f%%", g.density*100)
+			g.draw()
+		case 'u':
+			g.undo()
+		case 'e':
+			g.edit()
+		case 'v':
+			g.startSelection()
+		case 'z':
+			g.setZoom(g.zoom + 1)
+		case 'Z':
+			g.setZoom(g.zoom - 1)
+		case 'g', 's', 'o':
+			q := quickInserts[event.Rune()]
+			g.insert(q.name, q.flip)
+		case 'd':
+			g.heading = (g.heading + 1) % 4
+		case '[':
+			g.choose(-1)
+		case ']':
+			g.choose(1)
+		case 'b':
+			if r, ok := g.life.BoundingBox(); ok {
+				g.save()
+				g.life.Field().Shift(int(g.life.Width()-r.W)/2-int(r.X), int(g.life.Height()-r.H)/2-int(r.Y))
+				g.draw()
+			}
+		case 'B':
+			if r, ok := g.life.BoundingBox(); ok {
+				// The cropped board must not grow back with the terminal.
+				g.fitWidth, g.fitHeight = false, false
+				g.save()
+				g.life.Crop(r)
+				g.resize()
+			}
+		}
+	}
+	return false
+}
+
+// editKey handles the keys of the edit mode and reports whether the key was
+// consumed.
+func (g *game) editKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.editing = false
+	case tcell.KeyEnter:
+		p := g.stamp
+		if p == nil {
+			p = library[g.selected]
+		}
+		x, y := g.origin(p)
+		g.save()
+		p.Stamp(g.life.Field(), x, y)
+	case tcell.KeyRune:
+		if event.Rune() != ' ' {
+			return false
+		}
+		c := g.toBoard(g.cursorX-g.viewX, g.cursorY-g.viewY)
+		g.save()
+		g.life.Field().Set(uint(c.X), uint(c.Y), !g.life.Alive(c.X, c.Y))
+	default:
+		return false
+	}
+	g.draw()
+	return true
+}
+
+// edit enters the edit mode, placing the cursor at the center of the screen.
+func (g *game) edit() {
+	w, h, _, _ := g.cellsAt(g.boardSize())
+	c := g.toBoard(w/2, h/2)
+	g.cursorX, g.cursorY = c.X, c.Y
+	g.editing = true
+	g.draw()
+}
+
+// wrap maps v into [0, n), wrapping it toroidally like the board.
+func wrap(v, n int) int {
+	return (v%n + n) % n
+}
+
+// move moves the cursor one cell in the edit mode, or pans the viewport
+// otherwise.
+func (g *game) move(dx, dy int) {
+	if !g.editing {
+		g.panStep(dx, dy)
+		return
+	}
+	g.cursorX = wrap(g.cursorX+dx, int(g.life.Width()))
+	g.cursorY = wrap(g.cursorY+dy, int(g.life.Height()))
+	if g.sel != nil {
+		g.sel.cx, g.sel.cy = g.cursorX, g.cursorY
+	}
+	// Keep the cursor on the screen.
+	w, h, _, _ := g.cellsAt(g.boardSize())
+	if x := wrap(g.cursorX-g.viewX, int(g.life.Width())); x >= w {
+		g.viewX = wrap(g.cursorX-w/2, int(g.life.Width()))
+	}
+	if y := wrap(g.cursorY-g.viewY, int(g.life.Height())); y >= h {
+		g.viewY = wrap(g.cursorY-h/2, int(g.life.Height()))
+	}
+	g.draw()
+}
+
+// toScreen returns the screen region of the characters drawing the given
+// board position.
+func (g *game) toScreen(bx, by int) (x0, y0, w, h int) {
+	x, y := wrap(bx-g.viewX, int(g.life.Width())), wrap(by-g.viewY, int(g.life.Height()))
+	if g.zoom <= 0 {
+		f := g.cellsPerDot()
+		dw, dh := g.renderer.dots()
+		return x * g.cellWidth() / f / dw, y / f / dh, 1, 1
+	}
+	return x * g.zoom * g.cellWidth(), y * g.zoom, g.zoom * g.cellWidth(), g.zoom
+}
+
+// drawCursor highlights the characters under the cursor of the edit mode.
+func (g *game) drawCursor(cols, rows int) {
+	g.highlight(g.cursorX, g.cursorY, cols, rows)
+}
+
+// highlight draws the characters of the given board position with the style of
+// the cursor.
+func (g *game) highlight(bx, by, cols, rows int) {
+	x0, y0, w, h := g.toScreen(bx, by)
+	for j := y0; j < y0+h && j < rows; j++ {
+		for i := x0; i < x0+w && i < cols; i++ {
+			r, _, _, _ := g.screen.GetContent(i, j)
+			g.screen.SetContent(i, j, r, nil, g.theme.cursor)
+		}
+	}
+}
+
+// stampKey handles the keys of the stamp mode and reports whether the key was
+// consumed.
+func (g *game) stampKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.stamp = nil
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'r':
+			g.stamp = g.stamp.Rotate()
+		case 'f':
+			g.stamp = g.stamp.FlipHorizontal()
+		case 'F':
+			g.stamp = g.stamp.FlipVertical()
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	g.draw()
+	return true
+}
+
+// choose moves the library selection by delta patterns and enters the stamp
+// mode with the new selection. When not in stamp mode, it enters the stamp mode
+// with the current selection instead.
+func (g *game) choose(delta int) {
+	if g.stamp != nil {
+		g.selected = wrap(g.selected+delta, len(library))
+	}
+	g.stamp = library[g.selected]
+	g.draw()
+}
+
+// origin returns the board position of the top-left corner of p when centered
+// under the cursor of the edit mode, or the mouse pointer otherwise.
+func (g *game) origin(p *life.Pattern) (int, int) {
+	if g.editing {
+		return g.cursorX - int(p.Width)/2, g.cursorY - int(p.Height)/2
+	}
+	cx, cy, cw, ch := g.cellsAt(g.mouseX, g.mouseY)
+	return cx + cw/2 - int(p.Width)/2 + g.viewX, cy + ch/2 - int(p.Height)/2 + g.viewY
+}
+
+// quickInserts maps the keys that insert common objects to their library
+// names. The objects are flipped when needed to travel east or south-east.
+var quickInserts = map[rune]struct {
+	name string
+	flip bool
+}{
+	'g': {"Glider", false},
+	's': {"LWSS", true},
+	'o': {"Blinker", false},
+}
+
+// insert places the library pattern with the given name under the cursor or
+// the mouse pointer, turned to the current heading.
+func (g *game) insert(name string, flip bool) {
+	lib, ok := findPattern(name)
+	if !ok {
+		return
+	}
+	p := lib
+	if flip {
+		p = p.FlipHorizontal()
+	}
+	for i := 0; i < g.heading; i++ {
+		p = p.Rotate()
+	}
+	x, y := g.origin(p)
+	g.save()
+	p.Stamp(g.life.Field(), x, y)
+	g.draw()
+}
+
+const (
+	minInterval = time.Second / 2000
+	maxInterval = time.Second
+)
+
+// speedPresets holds the generations per second selected with Alt+1 to Alt+9.
+var speedPresets = [...]int{1, 2, 5, 10, 20, 50, 100, 500, 1000}
+
+// setInterval changes the time between generations, keeping it within the
+// supported limits.
+func (g *game) setInterval(d time.Duration) {
+	if d < minInterval {
+		d = minInterval
+	}
+	if d > maxInterval {
+		d = maxInterval
+	}
+	g.interval = d
+	g.tick.Reset(d)
+	g.draw()
+}
+
+// setSpeed changes the number of generations per second.
+func (g *game) setSpeed(gps int) {
+	g.setInterval(time.Second / time.Duration(gps))
+}
+
+const (
+	minZoom = -3
+	maxZoom = 2
+)
+
+// setZoom changes the zoom level, keeping it within the supported limits.
+func (g *game) setZoom(z int) {
+	if z < minZoom || z > maxZoom {
+		return
+	}
+	g.zoom = z
+	g.screen.Clear()
+	g.draw()
+}
+
+// cellsPerDot returns the width and height of the region of cells drawn with
+// every braille dot.
+func (g *game) cellsPerDot() int {
+	if g.zoom >= 0 {
+		return 1
+	}
+	return 1 << -g.zoom
+}
+
+// panStep moves the viewport an eighth of its size in the given direction.
+func (g *game) panStep(dx, dy int) {
+	w, h, _, _ := g.cellsAt(g.boardSize())
+	g.pan(dx*(w/8+1), dy*(h/8+1))
+}
+
+// clear kills all the cells of the board.
+func (g *game) clear() {
+	g.save()
+	g.life.Field().Clear()
+	g.draw()
+}
+
+// setRenderer changes the characters used to draw the board. The board keeps
+// its size, so the cells may no longer fill the screen.
+func (g *game) setRenderer(r renderer) {
+	if g.renderer.graphics() {
+		g.clearImages()
+	}
+	g.renderer = r
+	g.message = "Renderer " + g.renderer.String()
+	g.screen.Clear()
+	g.draw()
+}
+
+// setTheme changes the colors of the user interface.
+func (g *game) setTheme(t *theme) {
+	if g.palette != nil {
+		t = g.palette.apply(t)
+	}
+	g.theme = t
+	g.screen.SetStyle(t.base)
+	g.screen.Clear()
+}
+
+// themeIndex returns the position of the current theme in themes.
+func (g *game) themeIndex() int {
+	for i, t := range themes {
+		if t.name == g.theme.name {
+			return i
+		}
+	}
+	return 0
+}
+
+// reseed fills the board with a new random soup.
+func (g *game) reseed() {
+	g.save()
+	g.life.Field().Randomize(g.rng, g.density)
+	g.epoch = 0
+	g.sync()
+	g.draw()
+}
+
+// densities holds the soup densities cycled with the density key.
+var densities = [...]float64{0.1, 0.25, 0.5, 0.75, 1}
+
+// nextDensity returns the first density preset above d, wrapping around.
+func nextDensity(d float64) float64 {
+	for _, n := range densities {
+		if n > d {
+			return n
+		}
+	}
+	return densities[0]
+}
+
+// direction returns the unit vector pointed by an arrow key.
+func direction(k tcell.Key) (dx, dy int) {
+	switch k {
+	case tcell.KeyUp:
+		return 0, -1
+	case tcell.KeyDown:
+		return 0, 1
+	case tcell.KeyLeft:
+		return -1, 0
+	case tcell.KeyRight:
+		return 1, 0
+	}
+	return 0, 0
+}
+
+// brush describes the cells changed by a mouse click.
+type brush struct {
+	// size is the width of the brush in cells. Zero changes all the cells under
+	// the clicked character.
+	size  int
+	round bool
+}
+
+// cells calls fn with the coordinates of every cell of the brush when centered
+// at x, y.
+func (b brush) cells(x, y int, fn func(x, y int)) {
+	c := float64(b.size-1) / 2
+	r := float64(b.size) / 2
+	for j := 0; j < b.size; j++ {
+		for i := 0; i < b.size; i++ {
+			di, dj := float64(i)-c, float64(j)-c
+			if b.round && di*di+dj*dj > r*r {
+				continue
+			}
+			fn(x+i-b.size/2, y+j-b.size/2)
+		}
+	}
+}
+
+// cellsAt returns the viewport position and size of the region of cells under
+// the character at the given screen position.
+func (g *game) cellsAt(x, y int) (cx, cy, cw, ch int) {
+	if g.zoom > 0 {
+		return x / (g.zoom * g.cellWidth()), y / g.zoom, 1, 1
+	}
+	dw, dh := g.renderer.dots()
+	x0, y0, _, _ := g.dotRegion(x*dw, y*dh)
+	x1, y1, _, _ := g.dotRegion(x*dw+dw, y*dh+dh)
+	if x1 == x0 {
+		x1 = x0 + 1
+	}
+	return x0, y0, x1 - x0, y1 - y0
+}
+
+// pointerCells returns the board coordinates of the cells under the mouse
+// pointer, as a range when a character draws several cells.
+func (g *game) pointerCells() string {
+	cx, cy, cw, ch := g.cellsAt(g.mouseX, g.mouseY)
+	p := g.toBoard(cx, cy)
+	if cw == 1 && ch == 1 {
+		return fmt.Sprintf("Cell %d,%d", p.X, p.Y)
+	}
+	q := g.toBoard(cx+cw-1, cy+ch-1)
+	return fmt.Sprintf("Cells %d-%d,%d-%d", p.X, q.X, p.Y, q.Y)
+}
+
+// mouse handles a mouse event.
+func (g *game) mouse(event *tcell.EventMouse) {
+	g.recordMacro(event)
+	if g.tooSmall() || g.showStats {
+		return
+	}
+	g.mouseX, g.mouseY = event.Position()
+	// The coordinates of the status bar follow the pointer.
+	g.dirty = true
+	if cols, rows := g.boardSize(); g.mouseY >= rows || g.mouseX >= cols {
+		// The status bar and the comparison board are not part of the
+		// board.
+		g.pressed = event.Buttons() & tcell.ButtonMask(0xff)
+		g.pointer = false
+		return
+	}
+	cx, cy, _, _ := g.cellsAt(g.mouseX, g.mouseY)
+	g.pointer = cx < int(g.life.Width()) && cy < int(g.life.Height())
+	if wheel := event.Buttons() & (tcell.WheelUp | tcell.WheelDown); wheel != 0 {
+		g.wheel(wheel, event.Modifiers())
+		return
+	}
+	// Only process button events, not wheel events
+	button := event.Buttons() & tcell.ButtonMask(0xff)
+	pressed := button &^ g.pressed
+	released := g.pressed &^ button
+	g.pressed = button
+	if g.drag == nil && g.scrub(button) {
+		return
+	}
+	switch {
+	case pressed == tcell.Button2:
+		// A drag with the secondary button pans the viewport, while a click
+		// erases.
+		g.drag = &drag{x: g.mouseX, y: g.mouseY, viewX: g.viewX, viewY: g.viewY}
+		return
+	case g.drag != nil && button == tcell.Button2:
+		g.dragTo(g.mouseX, g.mouseY)
+	case g.drag != nil && released == tcell.Button2:
+		if !g.drag.moved && g.stamp == nil {
+			g.save()
+			g.paint(g.drag.x, g.drag.y, false)
+		} else if !g.drag.moved {
+			g.stamp = nil
+		}
+		g.drag = nil
+	case g.measuring != nil && pressed == tcell.Button1:
+		cx, cy, cw, ch := g.cellsAt(g.mouseX, g.mouseY)
+		g.measureAt(cx+cw/2, cy+ch/2)
+	case g.measuring != nil:
+		// The measurement mode does not change the board.
+		return
+	case g.sel != nil && pressed == tcell.Button1:
+		cx, cy, _, _ := g.cellsAt(g.mouseX, g.mouseY)
+		g.selectAt(cx, cy, true)
+	case g.sel != nil && button == tcell.Button1:
+		cx, cy, _, _ := g.cellsAt(g.mouseX, g.mouseY)
+		g.selectAt(cx, cy, false)
+	case g.sel != nil:
+		// Nothing changed.
+		return
+	case g.stamp != nil && pressed == tcell.Button1:
+		ox, oy := g.origin(g.stamp)
+		g.save()
+		g.stamp.Stamp(g.life.Field(), ox, oy)
+	case g.stamp != nil && pressed != tcell.ButtonNone:
+		g.stamp = nil
+	case g.stamp == nil && button != tcell.ButtonNone:
+		if pressed != tcell.ButtonNone {
+			// A whole stroke is undone at once.
+			g.save()
+		}
+		g.paint(g.mouseX, g.mouseY, button == tcell.Button1)
+	case g.stamp == nil:
+		// Nothing changed.
+		return
+	}
+	g.draw()
+}
+
+// wheel zooms around the mouse pointer, or changes the speed with Shift.
+func (g *game) wheel(wheel tcell.ButtonMask, mod tcell.ModMask) {
+	up := wheel&tcell.WheelUp != 0
+	if mod&tcell.ModShift != 0 {
+		if up {
+			g.setInterval(g.interval / 2)
+		} else {
+			g.setInterval(g.interval * 2)
+		}
+		return
+	}
+	z := g.zoom - 1
+	if up {
+		z = g.zoom + 1
+	}
+	if z < minZoom || z > maxZoom {
+		return
+	}
+	// Keep the cell under the pointer in place.
+	cx, cy, _, _ := g.cellsAt(g.mouseX, g.mouseY)
+	p := g.toBoard(cx, cy)
+	g.zoom = z
+	cx, cy, _, _ = g.cellsAt(g.mouseX, g.mouseY)
+	g.viewX, g.viewY = wrap(p.X-cx, int(g.life.Width())), wrap(p.Y-cy, int(g.life.Height()))
+	g.screen.Clear()
+	g.draw()
+}
+
+// drag holds the state of a viewport drag.
+type drag struct {
+	// x and y hold the screen position where the drag started.
+	x, y int
+	// viewX and viewY hold the viewport position when the drag started.
+	viewX, viewY int
+	moved        bool
+}
+
+// dragTo pans the viewport so the cell under the start of the drag moves to
+// the given screen position.
+func (g *game) dragTo(x, y int) {
+	x0, y0, _, _ := g.cellsAt(g.drag.x, g.drag.y)
+	x1, y1, _, _ := g.cellsAt(x, y)
+	if x0 == x1 && y0 == y1 && !g.drag.moved {
+		return
+	}
+	g.drag.moved = true
+	g.viewX, g.viewY = g.drag.viewX, g.drag.viewY
+	g.pan(x0-x1, y0-y1)
+}
+
+// pan moves the viewport by dx, dy cells. The viewport wraps around the edges
+// of the board.
+func (g *game) pan(dx, dy int) {
+	g.viewX = wrap(g.viewX+dx, int(g.life.Width()))
+	g.viewY = wrap(g.viewY+dy, int(g.life.Height()))
+	g.draw()
+}
+
+// toBoard returns the board position of the given viewport position.
+func (g *game) toBoard(x, y int) image.Point {
+	return image.Pt(wrap(x+g.viewX, int(g.life.Width())), wrap(y+g.viewY, int(g.life.Height())))
+}
+
+// paint sets the cells under the brush at the given screen position.
+func (g *game) paint(x, y int, alive bool) {
+	set := func(cx, cy int) {
+		// Clicks outside the board are ignored.
+		if cx >= 0 && cy >= 0 && uint(cx) < g.life.Width() && uint(cy) < g.life.Height() {
+			p := g.toBoard(cx, cy)
+			g.life.Field().Set(uint(p.X), uint(p.Y), alive)
+		}
+	}
+	cx, cy, cw, ch := g.cellsAt(x, y)
+	if g.brush.size == 0 {
+		for j := 0; j < ch; j++ {
+			for i := 0; i < cw; i++ {
+				set(cx+i, cy+j)
+			}
+		}
+		return
+	}
+	g.brush.cells(cx+cw/2, cy+ch/2, set)
+}
@@ -0,0 +1,126 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestPaint checks that painting under a panned view wraps the cells of the
+// board around the torus, and that the clicks outside the board are ignored.
+func TestPaint(t *testing.T) {
+	g := replayGame(t, "-width", "8", "-height", "4", "-density", "0")
+	g.zoom = 1
+	g.viewX, g.viewY = 6, 3
+	cw := g.cellWidth()
+	g.paint(3*cw, 2, true)
+	if !g.life.Field().Alive(1, 1) || g.life.Field().Population() != 1 {
+		t.Errorf("the cell 3,2 of the view is not the cell 1,1 of the board")
+	}
+	for _, c := range [][2]int{{8, 0}, {9, 2}, {0, 4}, {15, 7}} {
+		g.paint(c[0]*cw, c[1], true)
+	}
+	if g.life.Field().Population() != 1 {
+		t.Errorf("population %d after the clicks outside the board, want 1", g.life.Field().Population())
+	}
+	g.paint(3*cw, 2, false)
+	if g.life.Field().Population() != 0 {
+		t.Error("the cell is not erased")
+	}
+}
+
+// TestRewindStates checks that the kept generations of a Generations rule keep
+// their dying states, so stepping again after going back repeats the run.
+func TestRewindStates(t *testing.T) {
+	g := replayGame(t, "-rule", "Brian's Brain", "-width", "16", "-height", "16", "-density", "0.4")
+	// Two generations are kept, so the fields are reused from the third.
+	g.rewind.budget = 2 * 16 * 16
+	var hashes []uint64
+	for i := 0; i < 6; i++ {
+		g.step()
+		hashes = append(hashes, g.life.Field().Hash())
+	}
+	for i := 0; i < 2; i++ {
+		if !g.goBack() {
+			t.Fatalf("generation %d not kept", g.epoch-1)
+		}
+		if got, want := g.life.Field().Hash(), hashes[len(hashes)-2-i]; got != want {
+			t.Errorf("generation %d differs after going back", g.epoch)
+		}
+	}
+	for g.epoch < 6 {
+		g.step()
+		if g.life.Field().Hash() != hashes[g.epoch-1] {
+			t.Errorf("generation %d differs after stepping again", g.epoch)
+		}
+	}
+}
+
+// TestSeededSoups checks that the soups of a game depend on its seed only, and
+// not on the global generator, and that the seed command repeats them.
+func TestSeededSoups(t *testing.T) {
+	soup := func() *game {
+		opts, err := parseArgs("run", []string{"-width", "32", "-height", "16"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.seed = 7
+		screen := tcell.NewSimulationScreen("")
+		if err := screen.Init(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(screen.Fini)
+		return newGame(screen, opts)
+	}
+	a := soup()
+	rand.Int()
+	b := soup()
+	if a.life.Field().Hash() != b.life.Field().Hash() {
+		t.Error("the same seed gives another soup")
+	}
+	b.reseed()
+	if a.life.Field().Hash() == b.life.Field().Hash() {
+		t.Error("reseeding gives the same soup")
+	}
+	if b.execute("seed 7"); a.life.Field().Hash() != b.life.Field().Hash() {
+		t.Error("the seed command does not repeat the soup of the seed")
+	}
+}
+
+// TestCompareLockstep checks that undoing, going back and jumping to a bookmark
+// take the comparison board back to the same generation as the game board.
+func TestCompareLockstep(t *testing.T) {
+	g := replayGame(t, "-width", "24", "-height", "24", "-density", "0.4", "-compare", "HighLife")
+	for i := 0; i < 3; i++ {
+		g.step()
+	}
+	left, right := g.life.Field().Hash(), g.other.Field().Hash()
+	check := func(how string) {
+		t.Helper()
+		if g.epoch != 3 || g.life.Field().Hash() != left || g.other.Field().Hash() != right {
+			t.Errorf("%s: the boards are not the ones of generation 3", how)
+		}
+	}
+	g.mark(1, "")
+	g.save()
+	for i := 0; i < 3; i++ {
+		g.step()
+	}
+	g.undo()
+	check("undo")
+	for i := 0; i < 3; i++ {
+		g.step()
+	}
+	for i := 0; i < 3; i++ {
+		g.goBack()
+	}
+	check("back")
+	for i := 0; i < 3; i++ {
+		g.goForth()
+	}
+	g.jump(1)
+	check("jump")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// update rewrites the golden files with the current output, to be reviewed
+// with git diff:
+//
+//	go test -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// goldenBoard holds a glider, a blinker and a block, drawn by the golden tests
+// at generations 0 and 4.
+const goldenBoard = `!Name: golden
+.O..................
+..O.........OOO.....
+OOO.................
+....................
+........OO..........
+........OO..........
+....................
+....................
+`
+
+// goldenGenerations are the generations drawn by the golden tests.
+var goldenGenerations = []int{0, 4}
+
+// checkGolden compares got with the golden file of the given name, or rewrites
+// it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: the output changed, run with -update if on purpose:\n%s", name, got)
+	}
+}
+
+// screenText returns the characters of the rows of the screen above the status
+// bar, without the spaces at the end of the rows.
+func screenText(s tcell.Screen) string {
+	var b strings.Builder
+	cols, rows := s.Size()
+	for y := 0; y < rows-1; y++ {
+		var row strings.Builder
+		for x := 0; x < cols; {
+			r, comb, _, width := s.GetContent(x, y)
+			row.WriteRune(r)
+			for _, c := range comb {
+				row.WriteRune(c)
+			}
+			if width < 1 {
+				width = 1
+			}
+			x += width
+		}
+		b.WriteString(strings.TrimRight(row.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestGoldenRenderers(t *testing.T) {
+	f, _, err := life.ParsePlaintext([]byte(goldenBoard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []renderer{rendererBraille, rendererHalfBlocks, rendererASCII} {
+		screen := tcell.NewSimulationScreen("")
+		if err := screen.Init(); err != nil {
+			t.Fatal(err)
+		}
+		screen.SetSize(24, 10)
+		fs, finish := optionFlags("render")
+		if err := fs.Parse([]string{"-width", "20", "-height", "8", "-renderer", r.String()}); err != nil {
+			t.Fatal(err)
+		}
+		opts, err := finish(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := newGame(screen, opts)
+		var got bytes.Buffer
+		for _, gen := range goldenGenerations {
+			for int(g.epoch) < gen {
+				g.step()
+			}
+			g.draw()
+			fmt.Fprintf(&got, "Generation %d\n%s", gen, screenText(screen))
+		}
+		screen.Fini()
+		checkGolden(t, "render_"+r.String()+".txt", got.Bytes())
+	}
+}
+
+func TestGoldenString(t *testing.T) {
+	f, _, err := life.ParsePlaintext([]byte(goldenBoard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	birth, survival, _ := life.ParseRule("B3/S23")
+	l := life.NewLife(birth, survival, f.Width(), f.Height(), 0)
+	l.SetField(f)
+	var got bytes.Buffer
+	epoch := 0
+	for _, gen := range goldenGenerations {
+		for ; epoch < gen; epoch++ {
+			l.Step()
+		}
+		fmt.Fprintf(&got, "Generation %d\n%s\n", gen, l.String())
+	}
+	checkGolden(t, "string.txt", got.Bytes())
+}
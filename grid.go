@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// drawGrid highlights the characters on the chunk boundaries of the board and
+// labels the boundaries with their board coordinates along the top row and the
+// left column.
+func (g *game) drawGrid(cols, rows int) {
+	style := g.theme.base.Background(g.theme.grid)
+	// crosses reports whether a run of n cells starting at the board position
+	// v contains a boundary.
+	crosses := func(v, n int) bool {
+		return (v+n-1)/g.chunk*g.chunk >= v
+	}
+	// boundary returns the first boundary of a run of n cells starting at the
+	// board position v.
+	boundary := func(v int) int {
+		return (v + g.chunk - 1) / g.chunk * g.chunk
+	}
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			cx, cy, cw, ch := g.cellsAt(x, y)
+			if cx >= int(g.life.Width()) || cy >= int(g.life.Height()) {
+				continue
+			}
+			p := g.toBoard(cx, cy)
+			if crosses(p.X, cw) || crosses(p.Y, ch) {
+				r, _, old, _ := g.screen.GetContent(x, y)
+				fg, _, _ := old.Decompose()
+				g.screen.SetContent(x, y, r, nil, style.Foreground(fg))
+			}
+		}
+	}
+
+	// Label the boundaries in the first character showing them.
+	next := 0
+	for x, prev := 0, false; x < cols; x++ {
+		cx, _, cw, _ := g.cellsAt(x, 0)
+		if cx >= int(g.life.Width()) {
+			break
+		}
+		p := g.toBoard(cx, 0)
+		on := crosses(p.X, cw)
+		if on && !prev && x >= next {
+			next = g.drawLabel(x, 0, cols, style, strconv.Itoa(boundary(p.X))) + 1
+		}
+		prev = on
+	}
+	for y, prev := 1, false; y < rows; y++ {
+		_, cy, _, ch := g.cellsAt(0, y)
+		if cy >= int(g.life.Height()) {
+			break
+		}
+		p := g.toBoard(0, cy)
+		on := crosses(p.Y, ch)
+		if on && !prev {
+			g.drawLabel(0, y, cols, style, strconv.Itoa(boundary(p.Y)))
+		}
+		prev = on
+	}
+}
+
+// drawLabel draws text in the given row, starting at column x, and returns the
+// column after it.
+func (g *game) drawLabel(x, row, cols int, style tcell.Style, text string) int {
+	for _, r := range text {
+		if x >= cols {
+			break
+		}
+		g.screen.SetCell(x, row, style, r)
+		x++
+	}
+	return x
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// halt holds the conditions that stop the game on its own, for timed demos and
+// comparisons.
+type halt struct {
+	// maxGen is the generation where the game stops, or 0 to run forever.
+	maxGen uint
+	// exit reports whether the program exits when the game stops, instead of
+	// pausing.
+	exit bool
+	// untilStable reports whether the game stops when the board dies out or
+	// repeats. seen holds the hashes of the last boards, also in hashes
+	// oldest first, and count the number of boards recorded. stable is set
+	// once the board is reported stable, until it changes again, and died
+	// once it is reported dead.
+	untilStable bool
+	seen        map[uint64]sighting
+	hashes      []uint64
+	count       uint
+	stable      bool
+	died        bool
+}
+
+// sighting tells when a board was last seen: its position among all the
+// boards recorded, and its generation.
+type sighting struct {
+	n, epoch uint
+}
+
+// maxCycle is the number of boards kept to find the repeating ones, so longer
+// cycles are not found.
+const maxCycle = 1000
+
+// repeats records the board of the given generation, and returns the
+// generation where it was seen before, if it was.
+func (h *halt) repeats(f *life.Field, epoch uint) (uint, bool) {
+	if h.seen == nil {
+		h.seen = map[uint64]sighting{}
+	}
+	hash := f.Hash()
+	s, ok := h.seen[hash]
+	// Boards seen after this generation were left behind by going back or
+	// undoing, so they don't repeat.
+	ok = ok && s.epoch < epoch
+	h.seen[hash] = sighting{n: h.count, epoch: epoch}
+	h.hashes = append(h.hashes, hash)
+	h.count++
+	if len(h.hashes) > maxCycle {
+		// The oldest board is forgotten unless it was seen again.
+		if old := h.hashes[0]; h.seen[old].n == h.count-uint(len(h.hashes)) {
+			delete(h.seen, old)
+		}
+		h.hashes = h.hashes[1:]
+	}
+	return s.epoch, ok
+}
+
+// outcome tells why a run stopped on its own, and is the exit status of the
+// program then. The statuses 1 and 2 are left for the errors and the usage.
+type outcome int
+
+const (
+	outcomeRunning outcome = 0
+	outcomeExtinct outcome = 3
+	outcomeStable  outcome = 4
+	outcomeMaxGen  outcome = 5
+)
+
+// check records the board of l at the given generation, and tells whether the
+// run stops there with a summary of the reason: it reached the generation of
+// -max-gen, or it became stable with -until-stable.
+func (h *halt) check(l *life.Life, epoch uint) (outcome, string) {
+	if h.maxGen > 0 && epoch == h.maxGen {
+		return outcomeMaxGen, fmt.Sprintf("Reached generation %d with population %d", epoch, l.Population())
+	}
+	if !h.untilStable {
+		return outcomeRunning, ""
+	}
+	start, repeated := h.repeats(l.Field(), epoch)
+	population := l.Population()
+	dead := population == 0
+	if !repeated && !dead {
+		h.stable = false
+		return outcomeRunning, ""
+	}
+	// A repeating board dying out is reported again.
+	if h.stable && (h.died || !dead) {
+		return outcomeRunning, ""
+	}
+	h.stable, h.died = true, dead
+	if dead {
+		return outcomeExtinct, fmt.Sprintf("Died out at generation %d", epoch)
+	}
+	return outcomeStable, fmt.Sprintf("Stable since generation %d with period %d and population %d", start, epoch-start, population)
+}
+
+// halted stops the game if it reached the generation of -max-gen, or if it
+// became stable with -until-stable, and reports whether the program must exit.
+func (g *game) halted() bool {
+	o, reason := g.halt.check(g.life, g.epoch)
+	if o == outcomeRunning {
+		return false
+	}
+	g.outcome = o
+	logs.Info("stopped", "status", int(o), "reason", reason)
+	return g.stopRun(reason)
+}
+
+// stopRun pauses the game telling why, and reports whether the program must
+// exit instead.
+func (g *game) stopRun(reason string) bool {
+	g.message = reason
+	if g.halt.exit {
+		return true
+	}
+	g.paused = true
+	g.dirty = true
+	return false
+}
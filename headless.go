@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+	"github.com/kerrigan29a/go_life/pkg/render"
+)
+
+// runHeadless runs the generations of the options without a terminal and
+// writes the final board to w, in the RLE or plaintext format or drawn with the
+// characters of the renderer, or the hashes of the board with verify. With
+// -max-gen or -until-stable, it stops when the board reaches the generation or
+// becomes stable, and writes a summary to stderr, exiting with the status of
+// the outcome. No -generations runs until then.
+func runHeadless(opts options, w io.Writer) (err error) {
+	l := newBoard(opts, opts.width, opts.height, rand.New(rand.NewSource(opts.seed)))
+	if opts.verify > 0 {
+		return writeHashes(w, l, opts.generations, opts.verify)
+	}
+	var stream *statsStream
+	if opts.statsJSON != "" {
+		if stream, err = openStatsStream(opts.statsJSON); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := stream.Close(); err == nil {
+				err = cerr
+			}
+		}()
+	}
+	h := opts.halt
+	halts := h.maxGen > 0 || h.untilStable
+	o, summary := h.check(l, 0)
+	epoch := uint(0)
+	l.OnStep(func(gen uint64, st life.StepStats) {
+		epoch = uint(gen)
+		logs.Debug("step", "generation", epoch, "population", st.Population)
+		if stream != nil {
+			stream.write(l, epoch, st)
+		}
+		o, summary = h.check(l, epoch)
+	})
+	start := time.Now()
+	if o == outcomeRunning && (opts.generations > 0 || halts) {
+		l.Run(context.Background(), life.RunOptions{
+			Generations: uint64(opts.generations),
+			Until:       func(*life.Life) bool { return o != outcomeRunning },
+		})
+	}
+	logs.Info("render done", "generations", epoch, "population", l.Population(), "elapsed", time.Since(start))
+	if halts && o == outcomeRunning {
+		o, summary = outcomeMaxGen, fmt.Sprintf("Reached generation %d with population %d", epoch, l.Population())
+	}
+	if o != outcomeRunning {
+		fmt.Fprintln(os.Stderr, summary)
+		exitCode = int(o)
+	}
+	name := fmt.Sprintf("Generation %d", epoch)
+	switch opts.format {
+	case "rle":
+		return life.WriteRLE(w, name, l.Rule().String(), l.Field())
+	case "text":
+		_, err = fmt.Fprint(w, opts.renderer.text().Render(l, render.Full(l)))
+		return err
+	default:
+		return life.WritePlaintext(w, name, l.Field())
+	}
+}
+
+// writeHashes runs the generations of l, writing the generation and the hash of
+// the board at the start and every given number of generations. The hashes of
+// the same rule, size and seed are the same across releases.
+func writeHashes(w io.Writer, l *life.Life, generations, every uint) error {
+	for i := uint(0); ; i++ {
+		if i%every == 0 {
+			if _, err := fmt.Fprintf(w, "%d %016x\n", i, l.Field().Hash()); err != nil {
+				return err
+			}
+		}
+		if i == generations {
+			return nil
+		}
+		l.Step()
+	}
+}
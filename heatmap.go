@@ -0,0 +1,62 @@
+package main
+
+import "github.com/kerrigan29a/go_life/pkg/life"
+
+// heatWindow is the number of generations counted by the heatmap.
+const heatWindow = 100
+
+// heatmap counts how many times every cell changed in the last generations.
+type heatmap struct {
+	w, h   uint
+	counts [][]uint
+	// changes holds the cells changed by each of the last generations, oldest
+	// first, to take them out of the counts when they leave the window.
+	changes [][]life.CellChange
+}
+
+// newCounts returns a zeroed grid of counts of the given size.
+func newCounts(w, h uint) [][]uint {
+	counts := make([][]uint, h)
+	for y := range counts {
+		counts[y] = make([]uint, w)
+	}
+	return counts
+}
+
+func newHeatmap(w, h uint) *heatmap {
+	return &heatmap{w: w, h: h, counts: newCounts(w, h)}
+}
+
+// add counts the cells changed by the last generation of l. The counts start
+// again when the board changes size.
+func (m *heatmap) add(l *life.Life) {
+	if m.w != l.Width() || m.h != l.Height() {
+		*m = *newHeatmap(l.Width(), l.Height())
+	}
+	changed := l.Previous().Diff(l.Field())
+	for _, c := range changed {
+		m.counts[c.Y][c.X]++
+	}
+	m.changes = append(m.changes, changed)
+	if len(m.changes) > heatWindow {
+		for _, p := range m.changes[0] {
+			m.counts[p.Y][p.X]--
+		}
+		m.changes = m.changes[1:]
+	}
+}
+
+// toggleHeatmap shows or hides the heatmap, which shades the background by how
+// often the cells changed in the last generations.
+func (g *game) toggleHeatmap() {
+	switch {
+	case g.mono:
+		g.message = "No colors in monochrome mode"
+	case g.heatmap != nil:
+		g.heatmap = nil
+	default:
+		g.heatmap = newHeatmap(g.life.Width(), g.life.Height())
+		g.message = "Heatmap of the changes of the next generations"
+	}
+	g.draw()
+}
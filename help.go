@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keyHelp lists the keys shown by the help overlay. Keep it in sync with the
+// keymap of the README.
+var keyHelp = [...]struct{ keys, help string }{
+	{"?", "Show this help. The arrows, j, k and the page keys scroll it"},
+	{"ESC, Ctrl+C, q", "Exit"},
+	{"Ctrl+Z", "Suspend to the shell. Resume with fg"},
+	{":", "Type a command. Run :help to list them"},
+	{"p", "Pause / Resume"},
+	{"c", "Redraw the screen"},
+	{"C", "Cycle the cell coloring modes: none, age, density, activity, birth and turnover"},
+	{"H", "Show / hide a heatmap of how often the cells changed in the last 100 generations"},
+	{"T", "Cycle the color themes"},
+	{"L", "Show / hide the names of the still lifes, oscillators and spaceships of the library found on the board"},
+	{"S", "Show / hide a sparkline with the population of the last generations"},
+	{"I", "Switch between the board and a screen with the statistics of the last generations"},
+	{"#", "Show / hide a grid with chunks of 10 cells (see -chunk) labeled with their coordinates"},
+	{"G", "Cycle the renderers, keeping the board as it is"},
+	{"x, Delete", "Clear the board"},
+	{"=", "(On comparison) Copy the board to the comparison board"},
+	{"m0-m9", "Save the board in a bookmark"},
+	{"'0-'9", "Go back to the board saved in a bookmark"},
+	{"Ka-Kz / K", "Start recording the keys and clicks in a macro / Stop recording"},
+	{"&a-&z", "Play a macro"},
+	{"R", "Fill the board with a new random soup"},
+	{"D", "Cycle the density of the random soups"},
+	{"u / Ctrl+R", "Undo / redo the last change to the board, including generations stepped on pause"},
+	{"+ / -", "Double / halve the simulation speed"},
+	{"Alt+1-Alt+9", "Set the simulation speed to 1, 2, 5, 10, 20, 50, 100, 500 or 1000 generations per second"},
+	{"n", "(On pause) Next generation"},
+	{"a", "Show / hide the births and deaths of the next generation while paused"},
+	{"A", "Flash / stop flashing the cells born and dead in the last generation"},
+	{", / .", "(On pause) Step backward / forward through the last generations"},
+	{"Timeline", "(On pause) Click or drag the timeline on the last row to go to any of the last generations"},
+	{"Arrows, h, j, k, l", "Pan the view"},
+	{"@", "Follow the object under the mouse pointer, or all the live cells. Press again to stop"},
+	{"e", "Enter the edit mode, to change the board without a mouse"},
+	{"Arrows, h, j, k, l", "(On edit) Move the cursor"},
+	{"Space", "(On edit) Toggle the cell under the cursor"},
+	{"Enter", "(On edit) Stamp the chosen pattern under the cursor"},
+	{"ESC", "(On edit) Leave the edit mode"},
+	{"v", "Enter the selection mode. Drag with the mouse, or move the cursor on edit, to select a region"},
+	{"y", "(On selection) Copy the selected region"},
+	{"d", "(On selection) Cut the selected region"},
+	{"w", "(On selection) Save the selected region to a new plaintext file"},
+	{"Ctrl+V", "Paste the last copied region, with the same controls as the stamp mode"},
+	{"Shift+Arrows", "Shift the whole board one cell"},
+	{"r", "Rotate the board 90 degrees clockwise"},
+	{"f / F", "Mirror the board horizontally / vertically"},
+	{"z / Z", "Zoom in / out. Zooming in draws every cell with whole characters"},
+	{"1-9", "Set the brush size in cells"},
+	{"0", "Set the brush to all the cells under the clicked character"},
+	{"t", "Toggle between square and round brushes"},
+	{"M", "Enter the measurement mode. Click two cells to get the distance between them, and ESC to leave"},
+	{"i", "Open the pattern picker. Type to search, move with the arrows and press Enter to stamp"},
+	{"[ / ]", "Choose the previous / next pattern of the library and stamp it with a click"},
+	{"r, f, F", "(On stamp) Rotate or mirror the pattern before stamping it"},
+	{"ESC", "(On stamp) Leave the stamp mode"},
+	{"g, s, o", "Insert a glider, a lightweight spaceship or a blinker under the mouse pointer"},
+	{"d", "Turn the heading of the inserted objects 90 degrees clockwise"},
+	{"b", "Center the live cells on the board"},
+	{"B", "Crop the board to the live cells"},
+	{"Left click", "Turn ON the cells under the brush in the current position"},
+	{"Right click", "Turn OFF the cells under the brush in the current position"},
+	{"Middle click", "Turn OFF the cells under the brush in the current position"},
+	{"Right drag", "Pan the view"},
+	{"Wheel", "Zoom in / out around the mouse pointer"},
+	{"Shift+Wheel", "Double / halve the simulation speed"},
+	{"Pointer", "Show the board coordinates of the cells under the mouse pointer in the status bar"},
+}
+
+// helpLines returns the text of the help overlay: the keys, the bindings of the
+// configuration and the values of the flags.
+func helpLines() []string {
+	width := 0
+	for _, k := range keyHelp {
+		if len(k.keys) > width {
+			width = len(k.keys)
+		}
+	}
+	lines := []string{"Keys", ""}
+	for _, k := range keyHelp {
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, k.keys, k.help))
+	}
+	if b := bindingLines(); len(b) > 0 {
+		lines = append(lines, "", "Bindings of the configuration", "")
+		lines = append(lines, b...)
+	}
+	lines = append(lines, "", "Flags", "")
+	if flagSet != nil {
+		flagSet.VisitAll(func(f *flag.Flag) {
+			lines = append(lines, fmt.Sprintf("-%s = %s", f.Name, f.Value))
+		})
+	}
+	return lines
+}
+
+// helpKey handles the keys while the help overlay is shown and reports whether
+// the key was consumed. The arrows and the page keys scroll the overlay, any
+// other key hides it.
+func (g *game) helpKey(event *tcell.EventKey) bool {
+	if !g.showHelp {
+		return false
+	}
+	_, rows := g.size()
+	switch event.Key() {
+	case tcell.KeyUp:
+		g.helpScroll--
+	case tcell.KeyDown:
+		g.helpScroll++
+	case tcell.KeyPgUp:
+		g.helpScroll -= rows - 2
+	case tcell.KeyPgDn:
+		g.helpScroll += rows - 2
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'k':
+			g.helpScroll--
+		case 'j':
+			g.helpScroll++
+		default:
+			g.showHelp = false
+		}
+	default:
+		g.showHelp = false
+	}
+	g.screen.Clear()
+	g.draw()
+	return true
+}
+
+// drawHelp draws the help overlay in a box centered on the board.
+func (g *game) drawHelp(cols, rows int) {
+	lines := helpLines()
+	w := 0
+	for _, l := range lines {
+		if n := len([]rune(l)); n > w {
+			w = n
+		}
+	}
+	// Leave a margin of one character around the text.
+	w, h := w+2, len(lines)+2
+	if w > cols {
+		w = cols
+	}
+	if h > rows {
+		h = rows
+	}
+	if g.helpScroll > len(lines)-(h-2) {
+		g.helpScroll = len(lines) - (h - 2)
+	}
+	if g.helpScroll < 0 {
+		g.helpScroll = 0
+	}
+	x0, y0 := (cols-w)/2, (rows-h)/2
+	style := g.theme.status
+	for y := y0; y < y0+h; y++ {
+		text := ""
+		if i := y - y0 - 1 + g.helpScroll; y > y0 && y < y0+h-1 && i < len(lines) {
+			text = lines[i]
+		}
+		g.drawText(x0, y, x0+w, style, " "+text)
+	}
+}
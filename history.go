@@ -0,0 +1,106 @@
+package main
+
+import "github.com/kerrigan29a/go_life/pkg/life"
+
+// maxUndos is the number of states kept for undo.
+const maxUndos = 100
+
+// snapshot is a saved state of the game board.
+type snapshot struct {
+	field *life.Field
+	// other is the comparison board, if any, restored along with the game
+	// board so both stay in lockstep.
+	other *life.Field
+	epoch uint
+}
+
+// boards returns the state of the boards without copying them.
+func (g *game) boards() snapshot {
+	s := snapshot{field: g.life.Field(), epoch: g.epoch}
+	if g.other != nil {
+		s.other = g.other.Field()
+	}
+	return s
+}
+
+func (g *game) snapshot() snapshot {
+	return g.boards().copy()
+}
+
+// copy returns a copy of the snapshot with copies of its boards.
+func (s snapshot) copy() snapshot {
+	s.field = s.field.Copy()
+	if s.other != nil {
+		s.other = s.other.Copy()
+	}
+	return s
+}
+
+// cells returns the number of cells of the boards of the snapshot.
+func (s snapshot) cells() int {
+	n := int(s.field.Width() * s.field.Height())
+	if s.other != nil {
+		n += int(s.other.Width() * s.other.Height())
+	}
+	return n
+}
+
+func (g *game) restore(s snapshot) {
+	g.setBoards(s)
+	g.restored()
+}
+
+// setBoards replaces the boards with the ones of the snapshot without drawing
+// them. A snapshot taken before the comparison started gives the comparison
+// board a copy of the game board, like the sync key.
+func (g *game) setBoards(s snapshot) {
+	g.life.SetField(s.field)
+	g.epoch = s.epoch
+	switch {
+	case g.other == nil:
+	case s.other != nil:
+		g.other.SetField(s.other)
+	default:
+		g.other.SetField(s.field.Copy())
+	}
+}
+
+// restored redraws the screen after the board was replaced.
+func (g *game) restored() {
+	g.viewX, g.viewY = wrap(g.viewX, int(g.life.Width())), wrap(g.viewY, int(g.life.Height()))
+	g.screen.Clear()
+	g.draw()
+}
+
+// save records the current state of the board before a change, so it can be
+// undone.
+func (g *game) save() {
+	g.undos = append(g.undos, g.snapshot())
+	if len(g.undos) > maxUndos {
+		g.undos = g.undos[1:]
+	}
+	g.redos = nil
+	g.forward = nil
+}
+
+// undo goes back to the state before the last change.
+func (g *game) undo() {
+	if len(g.undos) == 0 {
+		return
+	}
+	g.redos = append(g.redos, g.snapshot())
+	s := g.undos[len(g.undos)-1]
+	g.undos = g.undos[:len(g.undos)-1]
+	g.restore(s)
+}
+
+// redo reapplies the last undone change.
+func (g *game) redo() {
+	if len(g.redos) == 0 {
+		return
+	}
+	g.undos = append(g.undos, g.snapshot())
+	s := g.redos[len(g.redos)-1]
+	g.redos = g.redos[:len(g.redos)-1]
+	g.restore(s)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// defaultCellW and defaultCellH hold the size in pixels assumed for the
+// characters when the terminal does not report it.
+const defaultCellW, defaultCellH = 10, 20
+
+// placedImage is an image of the board waiting to be written to the terminal
+// at column x of the first row, covering cols x rows characters.
+type placedImage struct {
+	x, cols, rows int
+	img           *image.RGBA
+}
+
+// cellSize returns the size in pixels of the characters of the terminal.
+func (g *game) cellSize() (w, h int) {
+	if w, h = cellPixels(); w > 0 && h > 0 {
+		return w, h
+	}
+	return defaultCellW, defaultCellH
+}
+
+// drawImage draws the viewport as an image, written to the terminal after the
+// characters are shown. The characters under it are left blank.
+func (g *game) drawImage(cols, rows int, next *life.Field) {
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			g.screen.SetCell(x, y, g.theme.base, ' ')
+		}
+	}
+	x := 0
+	if p, ok := g.screen.(*pane); ok {
+		x = p.x
+	}
+	cw, ch := g.cellSize()
+	g.images = append(g.images, placedImage{x: x, cols: cols, rows: rows, img: g.boardImage(cols, rows, cw, ch, next)})
+}
+
+// boardImage draws the viewport in an image covering cols x rows characters of
+// cw x ch pixels. Every dot of the renderer, or every cell when zoomed in, is a
+// rectangle of pixels.
+func (g *game) boardImage(cols, rows, cw, ch int, next *life.Field) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, cols*cw, rows*ch))
+	bg := toRGBA(g.theme.base, false)
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+	}
+
+	ghost := g.ghost()
+	born, dies := g.changes(next)
+	var nx, ny int
+	var pw, ph float64
+	region := func(i, j int) (x0, y0, x1, y1 int) { return i, j, i + 1, j + 1 }
+	if g.zoom > 0 {
+		pw, ph = float64(cw*g.zoom*g.dotWidth), float64(ch*g.zoom)
+		nx, ny = int(math.Ceil(float64(cols*cw)/pw)), int(math.Ceil(float64(rows*ch)/ph))
+	} else {
+		dw, dh := g.renderer.dots()
+		pw, ph = float64(cw)/float64(dw), float64(ch)/float64(dh)
+		nx, ny = cols*dw, rows*dh
+		region = g.dotRegion
+	}
+	for j := 0; j < ny; j++ {
+		for i := 0; i < nx; i++ {
+			x0, y0, x1, y1 := region(i, j)
+			if x0 >= int(g.life.Width()) || y0 >= int(g.life.Height()) {
+				continue
+			}
+			c, ok := g.dotColor(x0, y0, x1, y1, ghost, born, dies)
+			if !ok {
+				continue
+			}
+			r := image.Rect(int(float64(i)*pw), int(float64(j)*ph), int(float64(i+1)*pw), int(float64(j+1)*ph))
+			for y := r.Min.Y; y < r.Max.Y && y < img.Rect.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X && x < img.Rect.Max.X; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// dotColor returns the color of a dot covering the given region of the
+// viewport, reporting whether it is drawn at all. The born and dies functions
+// of changes highlight the cells changing, if given.
+func (g *game) dotColor(x0, y0, x1, y1 int, ghost map[image.Point]bool, born, dies func(x, y int) bool) (color.RGBA, bool) {
+	if g.anyIn(x0, y0, x1, y1, func(x, y int) bool { return ghost[g.toBoard(x, y)] }) {
+		return toRGBA(g.theme.base.Foreground(g.theme.ghost), true), true
+	}
+	if born != nil && g.anyIn(x0, y0, x1, y1, born) {
+		return toRGBA(g.theme.base.Foreground(g.theme.birth), true), true
+	}
+	if dies != nil && g.anyIn(x0, y0, x1, y1, dies) {
+		return toRGBA(g.theme.base.Foreground(g.theme.death), true), true
+	}
+	age := g.youngest(x0, y0, x1, y1)
+	if f := g.fading(x0, y0, x1, y1); age == 0 && f > 0 {
+		return toRGBA(g.trailStyle(f), true), true
+	}
+	if age == 0 {
+		return color.RGBA{}, false
+	}
+	return toRGBA(g.cellStyle(age), true), true
+}
+
+// toRGBA returns the foreground or background color of a style. The default
+// colors of the terminal are unknown, so they are taken as white on black.
+func toRGBA(style tcell.Style, foreground bool) color.RGBA {
+	fg, bg, _ := style.Decompose()
+	c, def := bg, color.RGBA{A: 0xff}
+	if foreground {
+		c, def = fg, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	}
+	r, gr, b := c.RGB()
+	if r < 0 {
+		return def
+	}
+	return color.RGBA{R: uint8(r), G: uint8(gr), B: uint8(b), A: 0xff}
+}
+
+// writeImages writes the images of the board to the terminal, saving and
+// restoring the cursor so the screen keeps its own position.
+func (g *game) writeImages() {
+	if g.out == nil || len(g.images) == 0 {
+		return
+	}
+	w := bufio.NewWriter(g.out)
+	for _, p := range g.images {
+		fmt.Fprintf(w, "\x1b7\x1b[1;%dH", p.x+1)
+		switch g.renderer {
+		case rendererSixel:
+			writeSixel(w, p.img)
+		case rendererKitty:
+			writeKitty(w, p.img)
+		case rendererITerm2:
+			writeITerm2(w, p.img, p.cols, p.rows)
+		}
+		fmt.Fprint(w, "\x1b8")
+	}
+	w.Flush()
+}
+
+// clearImages removes the images of the graphics renderers from the terminal.
+func (g *game) clearImages() {
+	if g.out == nil {
+		return
+	}
+	// Sixel and iTerm2 images are plain pixels, removed by redrawing the
+	// characters.
+	if g.renderer == rendererKitty {
+		clearKitty(g.out)
+	}
+	g.screen.Sync()
+}
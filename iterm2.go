@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+)
+
+// detectITerm2 reports whether the terminal is iTerm2, from the environment
+// variables it sets.
+func detectITerm2() bool {
+	return os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("LC_TERMINAL") == "iTerm2"
+}
+
+// writeITerm2 writes img as a PNG with the inline images of iTerm2, stretched
+// over the given number of characters.
+// See: https://iterm2.com/documentation-images.html
+func writeITerm2(w io.Writer, img *image.RGBA, cols, rows int) error {
+	var data bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := enc.Encode(&data, img); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0;doNotMoveCursor=1:%s\a",
+		data.Len(), cols, rows, base64.StdEncoding.EncodeToString(data.Bytes()))
+	return err
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// actions maps the names of the actions that can be bound to other keys to
+// their default keys. A bound key does what the first default key does.
+var actions = map[string]string{
+	"help":      "?",
+	"quit":      "qQ",
+	"command":   ":",
+	"pause":     "pP",
+	"redraw":    "c",
+	"color":     "C",
+	"heatmap":   "H",
+	"theme":     "T",
+	"sparkline": "S",
+	"stats":     "I",
+	"labels":    "L",
+	"grid":      "#",
+	"renderer":  "G",
+	"clear":     "x",
+	"sync":      "=",
+	"mark":      "m",
+	"jump":      "'",
+	"macro":     "K",
+	"play":      "&",
+	"reseed":    "R",
+	"density":   "D",
+	"undo":      "u",
+	"faster":    "+",
+	"slower":    "-",
+	"step":      "nN",
+	"preview":   "a",
+	"flash":     "A",
+	"back":      ",",
+	"forth":     ".",
+	"left":      "h",
+	"down":      "j",
+	"up":        "k",
+	"right":     "l",
+	"follow":    "@",
+	"edit":      "e",
+	"select":    "v",
+	"rotate":    "r",
+	"mirror":    "f",
+	"flip":      "F",
+	"zoom_in":   "z",
+	"zoom_out":  "Z",
+	"brush":     "t",
+	"picker":    "i",
+	"measure":   "M",
+	"previous":  "[",
+	"next":      "]",
+	"glider":    "g",
+	"spaceship": "s",
+	"blinker":   "o",
+	"heading":   "d",
+	"center":    "b",
+	"crop":      "B",
+}
+
+// bindings holds the keys of the actions bound in the configuration.
+var bindings = map[string]rune{}
+
+// keymap maps the keys changed by the bindings to the default keys of their
+// actions, or to 0 if they do nothing anymore.
+var keymap = map[rune]rune{}
+
+// loadKeys reads the bindings of the [keys] table of the configuration, like
+// pause = "space". The default keys of the bound actions do nothing, unless
+// they are bound to other actions.
+func loadKeys(c config) error {
+	var names []string
+	for name := range c["keys"] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := actions[name]; !ok {
+			return fmt.Errorf("keys.%s: unknown action", name)
+		}
+		s, _, err := c.String("keys", name)
+		if err != nil {
+			return err
+		}
+		key, err := parseKey(s)
+		if err != nil {
+			return fmt.Errorf("keys.%s: %w", name, err)
+		}
+		for other, k := range bindings {
+			if k == key {
+				return fmt.Errorf("keys.%s: %s is already bound to %s", name, s, other)
+			}
+		}
+		bindings[name] = key
+	}
+	for name := range bindings {
+		for _, r := range actions[name] {
+			keymap[r] = 0
+		}
+	}
+	for name, key := range bindings {
+		keymap[key] = []rune(actions[name])[0]
+	}
+	return nil
+}
+
+// parseKey reads a key name: a single character, or space.
+func parseKey(s string) (rune, error) {
+	if s == "space" {
+		return ' ', nil
+	}
+	if r, size := utf8.DecodeRuneInString(s); size > 0 && size == len(s) && r != utf8.RuneError {
+		return r, nil
+	}
+	return 0, fmt.Errorf("invalid key: %q", s)
+}
+
+// keyName returns the name of a key read by parseKey.
+func keyName(r rune) string {
+	if r == ' ' {
+		return "space"
+	}
+	return string(r)
+}
+
+// bindKey returns the event of the default key of the action bound to the key
+// of event, or nil if the key does nothing anymore. Any other event is
+// returned as is.
+func bindKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || event.Modifiers()&tcell.ModAlt != 0 {
+		return event
+	}
+	r, ok := keymap[event.Rune()]
+	if !ok {
+		return event
+	}
+	if r == 0 {
+		return nil
+	}
+	return tcell.NewEventKey(tcell.KeyRune, r, event.Modifiers())
+}
+
+// bindingLines returns the bindings of the configuration sorted by action.
+func bindingLines() []string {
+	var names []string
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s = %s", name, keyName(bindings[name])))
+	}
+	return lines
+}
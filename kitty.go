@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// kittyChunk is the largest payload of a kitty graphics command.
+const kittyChunk = 4096
+
+// detectKitty reports whether the terminal speaks the kitty graphics protocol.
+// The terminal cannot be queried while the screen owns its input, so this
+// relies on the environment variables set by the terminals known to support it.
+func detectKitty() bool {
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case strings.Contains(term, "kitty"), strings.Contains(term, "ghostty"):
+		return true
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WezTerm", "ghostty":
+		return true
+	}
+	return false
+}
+
+// writeKitty writes img as a PNG with the kitty graphics protocol, replacing
+// the image of the previous frame. The responses of the terminal are
+// suppressed, as they would arrive as key events, and the cursor does not move.
+// See: https://sw.kovidgoyal.net/kitty/graphics-protocol/
+func writeKitty(w io.Writer, img *image.RGBA) error {
+	var data bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := enc.Encode(&data, img); err != nil {
+		return err
+	}
+	payload := base64.StdEncoding.EncodeToString(data.Bytes())
+	b := bufio.NewWriter(w)
+	for first := true; first || len(payload) > 0; first = false {
+		chunk := payload
+		if len(chunk) > kittyChunk {
+			chunk = chunk[:kittyChunk]
+		}
+		payload = payload[len(chunk):]
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(b, "\x1b_Ga=T,f=100,i=1,p=1,q=2,C=1,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.Flush()
+}
+
+// clearKitty deletes the images written with the kitty graphics protocol.
+func clearKitty(w io.Writer) {
+	fmt.Fprint(w, "\x1b_Ga=d,q=2\x1b\\")
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"image"
+	"strings"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// maxLabelSize is the largest width and height of the recognized objects.
+const maxLabelSize = 20
+
+// maxLabelPeriod is the longest period of the recognized objects.
+const maxLabelPeriod = 15
+
+// labelRule is the rule of the objects of the library.
+var labelRule = life.Rule{Birth: []uint{3}, Survival: []uint{2, 3}}
+
+// known describes an object of the library.
+type known struct {
+	name string
+	// dx and dy hold the displacement of a spaceship every period, or zeros
+	// for still lifes and oscillators.
+	dx, dy int
+}
+
+// label is an object recognized on the board.
+type label struct {
+	known
+	// x and y hold the board position of the top left corner of the object.
+	x, y int
+}
+
+// catalog maps the shapes of the still lifes, oscillators and spaceships of the
+// library, in all their phases and orientations, to the objects. It is built
+// the first time the labels are shown, once the user patterns are loaded.
+var catalog map[string]known
+
+func buildCatalog() map[string]known {
+	c := map[string]known{}
+	for _, p := range library {
+		if p.Width > maxLabelSize || p.Height > maxLabelSize {
+			continue
+		}
+		// The four rotations of the pattern and of its mirror image.
+		f := p.Field()
+		for i := 0; i < 8; i++ {
+			if i == 4 {
+				f = f.Copy()
+				f.FlipHorizontal()
+			}
+			addPhases(c, p.Name, f)
+			f = f.Rotate()
+		}
+	}
+	return c
+}
+
+// addPhases adds the shapes of the phases of f to the catalog, if f comes back
+// to its first shape within maxLabelPeriod generations.
+func addPhases(c map[string]known, name string, f *life.Field) {
+	// The margin leaves room for the spaceships to move.
+	margin := uint(maxLabelPeriod + 2)
+	l := life.NewLife([]uint{3}, []uint{2, 3}, f.Width()+2*margin, f.Height()+2*margin, 0)
+	l.Field().Stamp(f, int(margin), int(margin))
+	var shapes []string
+	var start life.Rect
+	for gen := 0; gen <= maxLabelPeriod; gen++ {
+		r, ok := l.Field().BoundingBox()
+		if !ok {
+			return
+		}
+		shape := shapeKey(l.Field().Crop(r))
+		if gen == 0 {
+			start = r
+		} else if shape == shapes[0] {
+			k := known{name: name, dx: int(r.X) - int(start.X), dy: int(r.Y) - int(start.Y)}
+			for _, s := range shapes {
+				if _, ok := c[s]; !ok {
+					c[s] = k
+				}
+			}
+			return
+		}
+		shapes = append(shapes, shape)
+		l.Step()
+	}
+}
+
+// shapeKey returns a string telling apart the fields with different cells.
+func shapeKey(f *life.Field) string {
+	var b strings.Builder
+	for y := 0; y < int(f.Height()); y++ {
+		for x := 0; x < int(f.Width()); x++ {
+			if f.Alive(x, y) {
+				b.WriteByte('O')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('$')
+	}
+	return b.String()
+}
+
+// object is a group of live cells closer than two cells to each other, at
+// position x, y of the board.
+type object struct {
+	x, y  int
+	field *life.Field
+}
+
+// splitObjects returns the objects of f. The live cells closer than two cells
+// to each other make up an object, without wrapping around the edges, so the
+// objects touching other ones are not told apart.
+func splitObjects(f *life.Field) []object {
+	seen := life.NewField(f.Width(), f.Height())
+	var objects []object
+	f.ForEachAlive(func(x, y int) {
+		if seen.Alive(x, y) {
+			return
+		}
+		seen.Set(uint(x), uint(y), true)
+		cells := []image.Point{image.Pt(x, y)}
+		r := image.Rect(x, y, x+1, y+1)
+		for i := 0; i < len(cells); i++ {
+			c := cells[i]
+			for ny := c.Y - 2; ny <= c.Y+2; ny++ {
+				for nx := c.X - 2; nx <= c.X+2; nx++ {
+					if nx < 0 || ny < 0 || nx >= int(f.Width()) || ny >= int(f.Height()) || !f.Alive(nx, ny) || seen.Alive(nx, ny) {
+						continue
+					}
+					seen.Set(uint(nx), uint(ny), true)
+					cells = append(cells, image.Pt(nx, ny))
+					r = r.Union(image.Rect(nx, ny, nx+1, ny+1))
+				}
+			}
+		}
+		obj := life.NewField(uint(r.Dx()), uint(r.Dy()))
+		for _, c := range cells {
+			obj.Set(uint(c.X-r.Min.X), uint(c.Y-r.Min.Y), true)
+		}
+		objects = append(objects, object{x: r.Min.X, y: r.Min.Y, field: obj})
+	})
+	return objects
+}
+
+// findObjects returns the objects of the board found in the catalog. An object
+// touching another one is not recognized.
+func (g *game) findObjects() []label {
+	if catalog == nil {
+		catalog = buildCatalog()
+	}
+	var labels []label
+	for _, o := range splitObjects(g.life.Field()) {
+		if o.field.Width() > maxLabelSize || o.field.Height() > maxLabelSize {
+			continue
+		}
+		if k, ok := catalog[shapeKey(o.field)]; ok {
+			labels = append(labels, label{known: k, x: o.x, y: o.y})
+		}
+	}
+	return labels
+}
+
+// arrows holds the arrows showing the directions of the spaceships.
+var arrows = map[image.Point]string{
+	{1, 0}: "→", {-1, 0}: "←", {0, 1}: "↓", {0, -1}: "↑",
+	{1, 1}: "↘", {-1, 1}: "↙", {1, -1}: "↗", {-1, -1}: "↖",
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// toggleLabels shows or hides the names of the objects of the library found on
+// the board.
+func (g *game) toggleLabels() {
+	g.showLabels = !g.showLabels
+	if g.showLabels && !g.life.Rule().Equal(labelRule) {
+		g.message = "The labels only know the objects of " + labelRule.String()
+	}
+	g.draw()
+}
+
+// drawLabels writes the names of the recognized objects above them, with an
+// arrow pointing where the spaceships go.
+func (g *game) drawLabels(cols, rows int) {
+	if !g.life.Rule().Equal(labelRule) {
+		return
+	}
+	for _, l := range g.findObjects() {
+		text := l.name
+		if a, ok := arrows[image.Pt(sign(l.dx), sign(l.dy))]; ok {
+			text += " " + a
+		}
+		x, y, _, _ := g.toScreen(l.x, l.y)
+		if y > 0 {
+			y--
+		}
+		n := len([]rune(text))
+		if x >= cols || y >= rows {
+			continue
+		}
+		if x+n > cols {
+			n = cols - x
+		}
+		g.drawText(x, y, x+n, g.theme.status, text)
+	}
+}
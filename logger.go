@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logger writes diagnostics to a file as lines of key=value pairs, never to the
+// terminal, where they would fight with the screen of the game. Without a file
+// it discards them, and the debug ones are only written when verbose.
+type logger struct {
+	mu      sync.Mutex
+	out     io.Writer
+	verbose bool
+}
+
+// logs is the logger of the program, set up by -log and -verbose.
+var logs = &logger{}
+
+// openLog makes the logger write to the file at path, appending to it.
+func openLog(path string, verbose bool) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	logs.out, logs.verbose = f, verbose
+	return nil
+}
+
+// Debug logs the message with the given key and value pairs if verbose.
+func (l *logger) Debug(msg string, kv ...any) {
+	if l.verbose {
+		l.write("debug", msg, kv)
+	}
+}
+
+// Info logs the message with the given key and value pairs.
+func (l *logger) Info(msg string, kv ...any) {
+	l.write("info", msg, kv)
+}
+
+// Warn logs the message with the given key and value pairs.
+func (l *logger) Warn(msg string, kv ...any) {
+	l.write("warn", msg, kv)
+}
+
+// Error logs the message with the given key and value pairs.
+func (l *logger) Error(msg string, kv ...any) {
+	l.write("error", msg, kv)
+}
+
+// write writes a line with the time, the level, the message and the pairs,
+// quoting the values with spaces or quotes.
+func (l *logger) write(level, msg string, kv []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.out == nil {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "time=%s level=%s msg=%s", time.Now().Format(time.RFC3339Nano), level, logValue(msg))
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&b, " %v=%s", kv[i], logValue(fmt.Sprint(kv[i+1])))
+	}
+	b.WriteByte('\n')
+	// A failing log must not stop the game.
+	io.WriteString(l.out, b.String())
+}
+
+func logValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// macroKey handles the letter after K or & and reports whether the key was
+// consumed.
+func (g *game) macroKey(event *tcell.EventKey) bool {
+	if g.pending != 'K' && g.pending != '&' {
+		return false
+	}
+	pending := g.pending
+	g.pending = 0
+	r := event.Rune()
+	if event.Key() != tcell.KeyRune || r < 'a' || r > 'z' {
+		g.draw()
+		return true
+	}
+	if pending == 'K' {
+		g.startMacro(r)
+	} else {
+		g.playMacro(r)
+	}
+	return true
+}
+
+// startMacro starts recording the keys and the mouse events of the user in the
+// named macro, replacing it.
+func (g *game) startMacro(name rune) {
+	if g.macros == nil {
+		g.macros = map[rune][]tcell.Event{}
+	}
+	g.macros[name] = nil
+	g.recording = name
+	g.message = fmt.Sprintf("Recording macro %c, K to stop", name)
+	g.draw()
+}
+
+// stopMacro stops recording the macro, leaving out the key that stopped it.
+func (g *game) stopMacro() {
+	name := g.recording
+	events := g.macros[name]
+	g.macros[name] = events[:len(events)-1]
+	g.recording = 0
+	g.message = fmt.Sprintf("Macro %c: %d events", name, len(g.macros[name]))
+	g.draw()
+}
+
+// recordMacro adds an event of the user to the macro being recorded, if any.
+func (g *game) recordMacro(event tcell.Event) {
+	if g.recording != 0 && !g.playing {
+		g.macros[g.recording] = append(g.macros[g.recording], event)
+	}
+}
+
+// playMacro plays the events of the named macro at once. The macros do not
+// quit nor play other macros.
+func (g *game) playMacro(name rune) {
+	events, ok := g.macros[name]
+	if !ok || g.recording == name {
+		g.message = fmt.Sprintf("No macro %c", name)
+		g.draw()
+		return
+	}
+	if g.playing {
+		return
+	}
+	g.playing = true
+	defer func() { g.playing = false }()
+	for _, event := range events {
+		switch event := event.(type) {
+		case *tcell.EventKey:
+			g.key(event)
+		case *tcell.EventMouse:
+			g.mouse(event)
+		}
+	}
+	g.message = fmt.Sprintf("Played macro %c", name)
+	g.draw()
+}
+
+// macroNames returns the names of the recorded macros with their number of
+// events, sorted.
+func (g *game) macroNames() string {
+	var names []string
+	for name, events := range g.macros {
+		if name != g.recording {
+			names = append(names, fmt.Sprintf("%c: %d events", name, len(events)))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -8,218 +8,436 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
-	"regexp"
 	"runtime"
 	"strings"
 	"time"
-	"unicode"
 
-	"github.com/gdamore/tcell/v2"
-	"github.com/kerrigan29a/drawille-go"
-	"golang.org/x/exp/slices"
+	"github.com/kerrigan29a/go_life/pkg/life"
 )
 
-// Field represents a two-dimensional field of cells.
-type Field struct {
-	s    [][]bool
-	w, h uint
+// options holds the values given on the command line.
+type options struct {
+	rule          life.Rule
+	density       float64
+	width, height uint
+	square        bool
+	paused        bool
+	mono          bool
+	fps, gps      uint
+	colorMode     colorMode
+	theme         *theme
+	palette       *palette
+	renderer      renderer
+	chunk         uint
+	patterns      string
+	rewind        uint
+	trail         uint
+	screensaver   *screensaver
+	halt          halt
+	// compare is the rule of the comparison board, if any.
+	compare *life.Rule
+	// generations is the number of generations run by render and bench, and
+	// format the output of render.
+	generations uint
+	format      string
+	// statsJSON is the file where the statistics of every generation are
+	// written, if any.
+	statsJSON string
+	// soups is the number of soups run by search, from the one of seed, by
+	// workers goroutines.
+	soups     uint
+	seed      int64
+	workers   uint
+	catagolue catagolue
+	// record is the replay file written by run, if any, and replay the one
+	// played.
+	record string
+	replay *replay
+	// demo is the script played by run, if any.
+	demo *demo
+	// start is the pattern the board of render starts from, instead of a
+	// random soup.
+	start *life.Field
+	// scale is the size in pixels of the cells of the videos and the images
+	// written by render.
+	scale uint
+	// video is set when render writes a video, and frames when it writes
+	// images.
+	video  video
+	frames frames
+	// verify is the number of generations between the hashes of the board
+	// written by render instead of the board, if any.
+	verify uint
 }
 
-// NewField returns an empty field of the specified width and height.
-func NewField(w, h uint) *Field {
-	s := make([][]bool, h)
-	for i := range s {
-		s[i] = make([]bool, w)
-	}
-	return &Field{s: s, w: w, h: h}
-}
+// exitCode is the exit status of the program when it ends without errors.
+var exitCode int
 
-// Set sets the state of the specified cell to the given value.
-func (f *Field) Set(x, y uint, b bool) {
-	f.s[y][x] = b
-}
+// flagSet holds the flags of the running subcommand, shown by the help overlay.
+var flagSet *flag.FlagSet
 
-// Life stores the state of a round of Conway's Game of Life.
-type Life struct {
-	a, b            *Field
-	w, h            uint
-	birth, survival []uint
-}
-
-// NewLife returns a new Life game state with a random initial state.
-func NewLife(birth, survival []uint, w, h uint, maxDensity float64) *Life {
-	a := NewField(w, h)
-	for i := uint(0); i < uint(float64(w*h)*maxDensity); i++ {
-		a.Set(uint(rand.Intn(int(w))), uint(rand.Intn(int(h))), true)
-	}
-	return &Life{
-		a:        a,
-		b:        NewField(w, h),
-		w:        w,
-		h:        h,
-		birth:    birth,
-		survival: survival,
+// parseArgs reads the flags of the subcommands running the engine: run, render,
+// bench and search. render also takes a pattern file, or the name of a library
+// pattern, before or after the flags.
+func parseArgs(cmd string, args []string) (options, error) {
+	fs, finish := optionFlags(cmd)
+	flagSet = fs
+	if err := presetFlags(fs, cmd); err != nil {
+		return options{}, err
 	}
-}
-
-// Alive reports whether the specified cell is alive.
-// If the x or y coordinates are outside the field boundaries they are wrapped
-// toroidally. For instance, an x value of -1 is treated as width-1.
-func (l *Life) Alive(x, y int) bool {
-	return l.a.s[uint(y+int(l.a.h))%l.a.h][uint(x+int(l.a.w))%l.a.w]
-}
-
-func contains(x uint, xs []uint) bool {
-	_, ok := slices.BinarySearch(xs, x)
-	return ok
-}
-
-// Next returns the state of the specified cell at the next time step.
-func (l *Life) Next(x, y uint) bool {
-	// Count the adjacent cells that are alive.
-	neighbors := uint(0)
-	for i := -1; i <= 1; i++ {
-		for j := -1; j <= 1; j++ {
-			if (j != 0 || i != 0) && l.Alive(int(x)+i, int(y)+j) {
-				neighbors++
-			}
+	// The replay is read before the flags, so the flags given now are parsed
+	// once, overriding the recorded ones.
+	var r *replay
+	if path := flagValue(fs, args, "replay"); path != "" {
+		var err error
+		if r, err = readReplay(path); err != nil {
+			return options{}, err
 		}
+		args = append(append([]string{}, r.args...), args...)
 	}
-	// Return next state according to the game rules:
-	//   neighbors in BIRTH: on,
-	//   neighbors in SURVIVAL: maintain current state,
-	//   otherwise: off.
-	return contains(neighbors, l.birth) || contains(neighbors, l.survival) && l.Alive(int(x), int(y))
-}
-
-// Step advances the game by one instant, recomputing and updating all cells.
-func (l *Life) Step() {
-	// Update the state of the next field (b) from the current field (a).
-	for y := uint(0); y < l.h; y++ {
-		for x := uint(0); x < l.w; x++ {
-			l.b.Set(x, y, l.Next(x, y))
-		}
+	fs.Parse(args)
+	var file string
+	if cmd == "render" && fs.NArg() > 0 {
+		file = fs.Arg(0)
+		fs.Parse(fs.Args()[1:])
 	}
-	// Swap fields a and b.
-	l.a, l.b = l.b, l.a
-}
-
-// String returns the game board as a string.
-func (l *Life) String() string {
-	g := drawille.NewCanvas()
-	for y := 0; y < int(l.h); y++ {
-		for x := 0; x < int(l.w); x++ {
-			if l.Alive(x, y) {
-				g.Set(x, y)
-			}
+	if fs.NArg() > 0 {
+		return options{}, fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	var start *life.Field
+	if file != "" {
+		p, err := readPattern(file)
+		if lib, ok := libraryPattern(file); errors.Is(err, os.ErrNotExist) && ok {
+			p, err = lib, nil
+		}
+		if err != nil {
+			return options{}, err
 		}
+		start = p.Field()
+	}
+	opts, err := finish(start)
+	if err == nil && r != nil {
+		opts.replay = r
+		opts.seed, opts.width, opts.height = r.seed, r.w, r.h
 	}
-	return g.String()
+	return opts, err
 }
 
-func draw(screen tcell.Screen, l *Life) {
-	for y, line := range strings.Split(l.String(), "\n") {
-		pos := 0
-		for _, r := range line { // iterates over runes, not positions
-			screen.SetCell(pos, y, tcell.StyleDefault, r)
-			pos++
+// optionFlags defines the flags of the subcommands running the engine, and
+// returns them with the function that checks their values once parsed and
+// returns the options, starting from the given pattern if any, or the error of
+// the first invalid one. Every subcommand takes only its own flags, keeping the
+// defaults of the others.
+func optionFlags(cmd string) (*flag.FlagSet, func(start *life.Field) (options, error)) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintf(fs.Output(), "Usage of %s %s:\n", os.Args[0], cmd)
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output(), "")
+		if cmd == "run" {
+			printSubcommands(fs.Output())
 		}
+		fmt.Fprintf(fs.Output(), "Version: %s\n", Version)
+		fmt.Fprintln(fs.Output(), "")
 	}
-	screen.Show()
-}
+	run, render, bench, search := cmd == "run", cmd == "render", cmd == "bench", cmd == "search"
+
+	opts := options{
+		density:   0.5,
+		fps:       30,
+		gps:       10,
+		chunk:     10,
+		patterns:  userPatternDir(),
+		rewind:    64,
+		format:    "rle",
+		soups:     1000,
+		scale:     4,
+		video:     video{fps: 30},
+		catagolue: catagolue{key: "#anon"},
+	}
+	sizeHelp := "(0 fits the terminal)"
+	if bench {
+		opts.width, opts.height, opts.generations = 256, 256, 1000
+		sizeHelp = ""
+	}
+	if render {
+		sizeHelp = "(0 fits 80x24 characters with -format ansi)"
+	}
+	color, palette, renderer, theme, onStop := "none", "default", "auto", "default", "pause"
+	var compare life.Rule
+	var saverRules []life.Rule
+	var logPath string
+	var verbose bool
+	var videoSize, demoPath string
+	var saver, saverThemes bool
+
+	// The flags of the engine.
+	engineRule, _ := life.ParseNamedRule("B3/S23")
+	// The flags of the B/S and S/B notations are kept for the old scripts.
+	ruleFlags(fs, &engineRule, "`Rule` in the B/S notation of Golly, like B3/S23, or in the S/B notation of MCell, like 23/3", "rule", "bs", "golly", "sb", "mcell")
 
-func next(l *Life, screen tcell.Screen, epoch uint) uint {
-	l.Step()
-	draw(screen, l)
-	return epoch + 1
-}
+	densityHelp := "Initial `density`"
+	fs.Float64Var(&opts.density, "density", opts.density, fmt.Sprintf("%-35s %-20s", densityHelp, "(alias -d)"))
+	fs.Float64Var(&opts.density, "d", opts.density, fmt.Sprintf("%-35s %-20s", densityHelp, "(alias -density)"))
 
-func parseDigits(name, s string) []uint {
-	var result []uint
-	for _, r := range s {
-		if !unicode.IsDigit(r) || (r < '0' || r > '8') {
-			panic(fmt.Errorf("invalid %s rule, use only [0-8] digits: %s", name, s))
-		}
-		result = append(result, uint(r-'0'))
-	}
-	slices.Sort(result)
+	fs.StringVar(&logPath, "log", "", "Append the diagnostics to this `file`")
+	fs.BoolVar(&verbose, "verbose", false, "Log the details of every generation and soup too")
 
-	return result
-}
+	if !search {
+		fs.UintVar(&opts.width, "width", opts.width, strings.TrimSpace("Board `width` in cells "+sizeHelp))
+		fs.UintVar(&opts.height, "height", opts.height, strings.TrimSpace("Board `height` in cells "+sizeHelp))
+	}
 
-func parseBS(s string) ([]uint, []uint) {
-	re := regexp.MustCompile(`(?i)B([0-8]+)/S([0-8]*)`)
-	m := re.FindStringSubmatch(s)
-	if m == nil {
-		panic(fmt.Errorf("invalid B/S rule: %s", s))
+	if render || bench {
+		fs.UintVar(&opts.generations, "generations", opts.generations, "Number of `generations` to run (0 runs -format ansi forever)")
+	}
+	if render {
+		fs.StringVar(&opts.format, "format", opts.format, "Output `format`: the final board in rle or plaintext, or drawn by -renderer with text, or every frame as ANSI escape sequences with ansi")
+		fs.Int64Var(&opts.seed, "seed", 0, "`Seed` of the random soup (the current time by default)")
+		fs.UintVar(&opts.scale, "scale", opts.scale, "Size in `pixels` of the cells of -video and -out")
+		fs.StringVar(&opts.frames.out, "out", "", "Write PNG images of the generations to the files of this `pattern`, like frames/%04d.png, numbered from 0")
+		fs.UintVar(&opts.frames.every, "every", 1, "Number of `generations` between the images of -out")
+		fs.UintVar(&opts.frames.count, "frames", 0, "Number of images of -out (0 covers -generations)")
+		fs.StringVar(&opts.video.path, "video", "", "Write a video of the generations to this `file`, like out.mp4 or out.webm, encoded by ffmpeg")
+		fs.StringVar(&videoSize, "video-size", "", "`Size` of the video in pixels, like 1280x720 (-scale pixels per cell by default)")
+		fs.UintVar(&opts.video.fps, "video-fps", opts.video.fps, "Frames per second of the video, one generation each")
+		fs.DurationVar(&opts.video.duration, "video-duration", 0, "Length of the video, like 10s, instead of one frame for every generation of -generations")
+		fs.UintVar(&opts.verify, "verify", 0, "Write a hash of the board every this number of `generations` instead of the board, to compare engines and releases")
 	}
-	return parseDigits("birth", m[1]), parseDigits("survival", m[2])
-}
 
-func parseSB(s string) ([]uint, []uint) {
-	re := regexp.MustCompile(`([0-8]*)/([0-8]+)`)
-	m := re.FindStringSubmatch(s)
-	if m == nil {
-		panic(fmt.Errorf("invalid S/B rule: %s", s))
+	if search {
+		fs.UintVar(&opts.soups, "soups", opts.soups, "Number of random `soups` to run")
+		fs.Int64Var(&opts.seed, "seed", opts.seed, "`Seed` of the first soup, every next one taking the next seed")
+		fs.UintVar(&opts.workers, "workers", opts.workers, "Number of soups run at once (0 uses all the CPU cores)")
+		fs.StringVar(&opts.catagolue.root, "root", "", "Make the soups from the SHA-256 hashes of their ids, this `root` followed by their numbers, like Catagolue")
+		fs.BoolVar(&opts.catagolue.submit, "submit", false, "Submit the census to Catagolue, with a random root if -root is not given")
+		fs.StringVar(&opts.catagolue.url, "catagolue", opts.catagolue.url, "`URL` of the Catagolue test server the census is submitted to")
+		fs.StringVar(&opts.catagolue.key, "key", opts.catagolue.key, "Payosha256 `key` of the submissions to Catagolue")
 	}
-	return parseDigits("survival", m[1]), parseDigits("birth", m[2])
-}
 
-func parseArgs() (birth, survival []uint, density float64) {
-	flag.Usage = func() {
-		fmt.Fprintln(flag.CommandLine.Output(), "")
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
-		fmt.Fprintln(flag.CommandLine.Output(), "")
-		flag.PrintDefaults()
-		fmt.Fprintln(flag.CommandLine.Output(), "")
-		fmt.Fprintf(flag.CommandLine.Output(), "Version: %s\n", Version)
-		fmt.Fprintln(flag.CommandLine.Output(), "")
+	// The flags of the drawing.
+	if run || render {
+		fs.BoolVar(&opts.square, "square", false, "Draw every cell two dots wide so it looks square")
+		fs.BoolVar(&opts.mono, "mono", false, "Draw in pure black and white, without any color escape")
+		fs.StringVar(&color, "color", color, "Cell coloring `mode` (none, age, density, activity, birth, turnover)")
+		fs.StringVar(&palette, "palette", palette, "Color `palette` safe for a color vision deficiency (default, protanopia, deuteranopia, tritanopia)")
+		fs.StringVar(&renderer, "renderer", renderer, "Cell `renderer` (braille, blocks, half-blocks, sextants, ascii, wide, emoji, sixel, kitty, iterm2 or auto)")
+		fs.StringVar(&theme, "theme", theme, "Color `theme` (default, matrix, amber, paper, ocean or one of the configuration file)")
+		fs.UintVar(&opts.trail, "trail", 0, fmt.Sprintf("Draw the cells dead in the last `generations` fading out, up to %d", maxTrail))
+		fs.UintVar(&opts.gps, "gps", opts.gps, "Generations per second")
+
+		fs.UintVar(&opts.halt.maxGen, "max-gen", 0, "Stop after this number of `generations` (0 runs forever)")
+		fs.BoolVar(&opts.halt.untilStable, "until-stable", false, "Stop when the board dies out or repeats, telling the generation where it became stable")
+		fs.StringVar(&opts.statsJSON, "stats-json", "", "Write the statistics of every generation as JSON lines to this `file` (- for the standard output, only with render)")
 	}
 
-	var bs string
-	bsDefault := "B3/S23"
-	bsHelp := "Birth/Survival (or Golly) `rule`"
-	flag.StringVar(&bs, "bs", bsDefault, fmt.Sprintf("%-35s %-20s", bsHelp, "(alias -golly)"))
-	flag.StringVar(&bs, "golly", bsDefault, fmt.Sprintf("%-35s %-20s", bsHelp, "(alias -bs)"))
+	// The flags of the terminal.
+	if run {
+		fs.StringVar(&onStop, "on-stop", onStop, "What to do when the game stops on its own (`pause` or exit)")
+		fs.UintVar(&opts.chunk, "chunk", opts.chunk, "Size in cells of the chunks of the grid overlay")
+		fs.StringVar(&opts.patterns, "patterns", opts.patterns, "User pattern `directory` of plaintext (.cells), RLE (.rle) and Life 1.05/1.06 (.lif) patterns added to the library")
+		fs.UintVar(&opts.rewind, "rewind", opts.rewind, "Memory in `MiB` used to keep the last generations to step back through them")
+		ruleFlags(fs, &compare, "Compare side by side with a copy of the board running this `rule`", "compare")
+		fs.BoolVar(&saver, "screensaver", false, "Reseed the board when it dies out or settles, to run unattended")
+		fs.Var(rulesFlag{&saverRules}, "screensaver-rules", "Comma-separated `rules` cycled on every restart of the screensaver")
+		fs.BoolVar(&saverThemes, "screensaver-themes", false, "Cycle the themes on every restart of the screensaver")
+		fs.UintVar(&opts.fps, "fps", opts.fps, "Screen refreshes per second")
+		fs.StringVar(&opts.record, "record", "", "Record the flags, the seed and the keys and mouse events of the run to this replay `file`")
+		// The replay is read by parseArgs before the flags are parsed.
+		fs.String("replay", "", "Play the run recorded in this replay `file`, with the flags given after it overriding the recorded ones")
+		fs.StringVar(&demoPath, "demo", "", "Play the demo script in this `file`, running commands of the command line at given times")
+		fs.BoolVar(&opts.paused, "paused", false, "Start paused on the first generation, to look at it or edit it before running")
+	}
 
-	var sb string
-	sbDefault := "23/3"
-	sbHelp := "Survival/Birth (or MCell) `rule`"
-	flag.StringVar(&sb, "sb", sbDefault, fmt.Sprintf("%-35s %-20s", sbHelp, "(alias -mcell)"))
-	flag.StringVar(&sb, "mcell", sbDefault, fmt.Sprintf("%-35s %-20s", sbHelp, "(alias -sb)"))
+	return fs, func(start *life.Field) (options, error) {
+		if start != nil {
+			// The board fits the pattern with a margin along the axes
+			// without a size.
+			opts.start = start
+			if opts.width == 0 {
+				opts.width = start.Width() + 2*patternMargin
+			}
+			if opts.height == 0 {
+				opts.height = start.Height() + 2*patternMargin
+			}
+		}
+		if logPath != "" {
+			if err := openLog(logPath, verbose); err != nil {
+				return options{}, err
+			}
+		}
+		if render && !flagGiven(fs, "seed") {
+			opts.seed = time.Now().UnixNano()
+		}
 
-	densityDefault := 0.5
-	densityHelp := "Initial `density`"
-	flag.Float64Var(&density, "density", densityDefault, fmt.Sprintf("%-35s %-20s", densityHelp, "(alias -d)"))
-	flag.Float64Var(&density, "d", densityDefault, fmt.Sprintf("%-35s %-20s", densityHelp, "(alias -density)"))
+		opts.rule = engineRule
+		var err error
+		if opts.colorMode, err = parseColorMode(color); err != nil {
+			return options{}, err
+		}
+		if opts.theme, err = findTheme(theme); err != nil {
+			return options{}, err
+		}
+		if opts.palette, err = findPalette(palette); err != nil {
+			return options{}, err
+		}
+		if (opts.mono || render) && renderer == "auto" {
+			renderer = rendererBraille.String()
+		}
+		if opts.renderer, err = parseRenderer(renderer); err != nil {
+			return options{}, err
+		}
+		if opts.mono && opts.renderer.graphics() {
+			return options{}, fmt.Errorf("the %s renderer draws colors, it cannot be used with -mono", opts.renderer)
+		}
+		if render && opts.renderer.graphics() {
+			return options{}, fmt.Errorf("the %s renderer writes images, it cannot be used with render", opts.renderer)
+		}
+		if opts.mono && opts.colorMode != colorNone {
+			return options{}, fmt.Errorf("the %s color mode draws colors, it cannot be used with -mono", opts.colorMode)
+		}
+		if compare.Birth != nil {
+			opts.compare = &compare
+		}
+		if saver {
+			opts.screensaver = &screensaver{rules: saverRules, themes: saverThemes}
+		}
+		if opts.fps == 0 || opts.gps == 0 {
+			return options{}, errors.New("fps and gps must be positive")
+		}
+		if opts.chunk == 0 {
+			return options{}, errors.New("chunk must be positive")
+		}
+		switch onStop {
+		case "pause":
+		case "exit":
+			opts.halt.exit = true
+		default:
+			return options{}, fmt.Errorf("invalid stop action: %s", onStop)
+		}
+		switch opts.format {
+		case "rle", "plaintext", "text":
+			if render && (opts.width == 0 || opts.height == 0) {
+				return options{}, fmt.Errorf("the %s format needs -width and -height", opts.format)
+			}
+		case "ansi":
+			if opts.statsJSON == "-" {
+				return options{}, errors.New("the statistics and the frames cannot both be written to the standard output")
+			}
+		default:
+			return options{}, fmt.Errorf("invalid format: %s", opts.format)
+		}
+		if opts.statsJSON == "-" && !render {
+			return options{}, errors.New("the statistics can only be written to the standard output with render")
+		}
+		if bench && (opts.width == 0 || opts.height == 0 || opts.generations == 0) {
+			return options{}, errors.New("width, height and generations must be positive")
+		}
+		if demoPath != "" {
+			if opts.demo, err = readDemo(demoPath); err != nil {
+				return options{}, err
+			}
+		}
+		if opts.scale == 0 {
+			return options{}, errors.New("scale must be positive")
+		}
+		if opts.frames.out != "" && opts.frames.every == 0 {
+			return options{}, errors.New("every must be positive")
+		}
+		if opts.frames.out != "" && opts.frames.count == 0 && opts.generations == 0 {
+			return options{}, errors.New("the images need -generations or -frames")
+		}
+		if videoSize != "" {
+			if opts.video.w, opts.video.h, err = parseVideoSize(videoSize); err != nil {
+				return options{}, err
+			}
+		}
+		if opts.video.path != "" && opts.video.fps == 0 {
+			return options{}, errors.New("the video needs a positive -video-fps")
+		}
+		if opts.video.path != "" && opts.generations == 0 && opts.video.duration <= 0 {
+			return options{}, errors.New("the video needs -generations or -video-duration")
+		}
+		if opts.verify > 0 && opts.format == "ansi" {
+			return options{}, errors.New("the hashes cannot be written with the ansi format")
+		}
+		if search && opts.soups == 0 {
+			return options{}, errors.New("soups must be positive")
+		}
+		if (opts.catagolue.root != "" || opts.catagolue.submit) && opts.density != 0.5 {
+			return options{}, errors.New("the soups of Catagolue have a density of 0.5")
+		}
+		if err := opts.catagolue.check(opts.rule); err != nil {
+			return options{}, err
+		}
+		if opts.trail > maxTrail {
+			return options{}, fmt.Errorf("trail must be up to %d generations", maxTrail)
+		}
+		if opts.mono && opts.trail > 0 {
+			return options{}, errors.New("the trails draw colors, they cannot be used with -mono")
+		}
+		logs.Info("start", "version", Version, "subcommand", cmd, "rule", opts.rule.String(), "width", opts.width, "height", opts.height, "density", opts.density, "renderer", opts.renderer, "color", opts.colorMode)
+		return opts, nil
+	}
+}
 
-	flag.Parse()
+// flagGiven tells whether the named flag was given on the command line.
+func flagGiven(fs *flag.FlagSet, name string) bool {
+	given := false
+	fs.Visit(func(f *flag.Flag) {
+		given = given || f.Name == name
+	})
+	return given
+}
 
-	if bs != bsDefault {
-		birth, survival = parseBS(bs)
-	} else {
-		survival, birth = parseSB(sb)
-	}
-	if birth == nil {
-		panic("unknown parsing state")
+// flagValue returns the last value of the named flag of fs in args, without
+// parsing them, or nothing if it is not given.
+func flagValue(fs *flag.FlagSet, args []string, name string) string {
+	value := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+		n, v, hasValue := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+		f := fs.Lookup(n)
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); !hasValue && !(ok && b.IsBoolFlag()) && i+1 < len(args) {
+			i++
+			v = args[i]
+		}
+		if n == name {
+			value = v
+		}
 	}
-	return birth, survival, density
+	return value
 }
 
+// handleErrors is the last resort guard of the errors panicked deep in the
+// frontends, where returning them would add error checks everywhere. It is
+// only possible because the code does not update shared state and does not
+// manipulate locks. The parsers and the library return their errors instead.
 func handleErrors() {
-	// This code allows us to propagate internal errors without having to add error checks everywhere throughout the
-	// code. This is only possible because the code does not update shared state and does not manipulate locks.
 	if r := recover(); r != nil {
 		var rerr runtime.Error
 		if err, ok := r.(error); ok && !errors.As(err, &rerr) {
-			log.Fatalf("%+v", err)
+			fail(err)
 		} else {
 			panic(r)
 		}
 	}
-	os.Exit(0)
+	os.Exit(exitCode)
+}
+
+// fail logs and writes err, and exits with status 1.
+func fail(err error) {
+	logs.Error("exit", "error", err)
+	log.Fatalf("%+v", err)
 }
 
 func main() {
@@ -229,85 +447,18 @@ func main() {
 	//     - https://github.com/golang/go/blob/865911424d509184d95d3f9fc6a8301927117fdc/src/encoding/json/encode.go#L322
 	defer handleErrors()
 
-	birth, survival, density := parseArgs()
-
-	// Initialize screen
-	screen, err := tcell.NewScreen()
+	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatalf("%+v", err)
+		fail(err)
 	}
-	if err := screen.Init(); err != nil {
-		log.Fatalf("%+v", err)
+	userConfig = cfg
+	if err := loadThemes(cfg); err != nil {
+		fail(err)
 	}
-	defer screen.Fini()
-	screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorReset).Foreground(tcell.ColorReset))
-	screen.EnableMouse()
-	screen.DisablePaste()
-	screen.HideCursor()
-	screen.Clear()
-
-	rand.Seed(time.Now().UnixNano())
-	w, h := screen.Size()
-	if err != nil {
-		panic(err)
+	if err := loadKeys(cfg); err != nil {
+		fail(err)
 	}
-	l := NewLife(birth, survival, uint(w*2), uint(h*4), density)
-
-	tick := time.NewTicker(time.Second / 10)
-
-	events := make(chan tcell.Event)
-	go func() {
-		for {
-			events <- screen.PollEvent()
-		}
-	}()
-
-	epoch := uint(0)
-	paused := false
-loop:
-	for {
-		select {
-		case event := <-events:
-			switch event := event.(type) {
-			case *tcell.EventResize:
-				screen.Sync()
-			case *tcell.EventKey:
-				if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC {
-					break loop
-				}
-				if unicode.ToLower(event.Rune()) == 'q' {
-					break loop
-				}
-				if unicode.ToLower(event.Rune()) == 'p' {
-					paused = !paused
-				} else if unicode.ToLower(event.Rune()) == 'c' {
-					screen.Sync()
-				} else if unicode.ToLower(event.Rune()) == 'n' && paused {
-					epoch = next(l, screen, epoch)
-				}
-
-			case *tcell.EventMouse:
-				button := event.Buttons()
-				// Only process button events, not wheel events
-				button &= tcell.ButtonMask(0xff)
-				if button != tcell.ButtonNone {
-					x, y := event.Position()
-					l.a.Set(uint(x*2)+0, uint(y*4)+0, button == tcell.Button1)
-					l.a.Set(uint(x*2)+0, uint(y*4)+1, button == tcell.Button1)
-					l.a.Set(uint(x*2)+0, uint(y*4)+2, button == tcell.Button1)
-					l.a.Set(uint(x*2)+0, uint(y*4)+3, button == tcell.Button1)
-					l.a.Set(uint(x*2)+1, uint(y*4)+0, button == tcell.Button1)
-					l.a.Set(uint(x*2)+1, uint(y*4)+1, button == tcell.Button1)
-					l.a.Set(uint(x*2)+1, uint(y*4)+2, button == tcell.Button1)
-					l.a.Set(uint(x*2)+1, uint(y*4)+3, button == tcell.Button1)
-					draw(screen, l)
-				}
-			}
-		case <-tick.C:
-			if paused {
-				continue
-			}
-			epoch = next(l, screen, epoch)
-		}
+	if err := runSubcommand(os.Args[1:]); err != nil {
+		fail(err)
 	}
 }
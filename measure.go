@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// measure holds the points clicked on the measurement mode, in board
+// coordinates.
+type measure struct {
+	ax, ay, bx, by int
+	// points is the number of points placed, up to 2.
+	points int
+}
+
+// distance returns the displacement between the points and their Chebyshev
+// distance, the number of generations a signal at the speed of light takes to
+// go from one to the other. As the board wraps around its edges, the shortest
+// way is taken.
+func (g *game) distance() (dx, dy, d int) {
+	m := g.measuring
+	w, h := int(g.life.Width()), int(g.life.Height())
+	dx = wrap(m.bx-m.ax+w/2, w) - w/2
+	dy = wrap(m.by-m.ay+h/2, h) - h/2
+	d = abs(dx)
+	if abs(dy) > d {
+		d = abs(dy)
+	}
+	return dx, dy, d
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// measureText returns the result of the measurement shown on the board and
+// the status bar.
+func (g *game) measureText() string {
+	dx, dy, d := g.distance()
+	return fmt.Sprintf("dx %d, dy %d, distance %d", dx, dy, d)
+}
+
+// startMeasure enters the measurement mode, where two clicks measure the
+// distance between two cells.
+func (g *game) startMeasure() {
+	g.measuring = &measure{}
+	g.message = "Click two cells to measure the distance between them"
+	g.draw()
+}
+
+// measureKey handles the keys of the measurement mode and reports whether the
+// key was consumed. ESC leaves the mode.
+func (g *game) measureKey(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyEscape {
+		return false
+	}
+	g.measuring = nil
+	g.draw()
+	return true
+}
+
+// measureAt places the next point of the measurement on the given viewport
+// position. A third click starts a new measurement.
+func (g *game) measureAt(x, y int) {
+	p := g.toBoard(x, y)
+	m := g.measuring
+	if m.points == 2 {
+		m.points = 0
+	}
+	if m.points == 0 {
+		m.ax, m.ay = p.X, p.Y
+	}
+	m.bx, m.by = p.X, p.Y
+	m.points++
+}
+
+// drawMeasure highlights the points of the measurement and labels the second
+// one with the result.
+func (g *game) drawMeasure(cols, rows int) {
+	m := g.measuring
+	if m.points == 0 {
+		return
+	}
+	g.highlight(m.ax, m.ay, cols, rows)
+	if m.points < 2 {
+		return
+	}
+	g.highlight(m.bx, m.by, cols, rows)
+	x, y, w, _ := g.toScreen(m.bx, m.by)
+	text := " " + g.measureText() + " "
+	// The label goes on the left of the point when it does not fit on the
+	// right.
+	x += w
+	if x+len(text) > cols {
+		x -= w + len(text)
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < rows {
+		g.drawText(x, y, x+len(text), g.theme.status, text)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// monoScreen is a screen that draws in pure black and white, for e-ink
+// terminals, projectors or logs. It drops every color so no color escape
+// reaches the terminal, keeping only the attributes. The characters with a
+// background other than the one of the board, like the status bar, the cursor
+// or the selection, are drawn in reverse video instead.
+type monoScreen struct {
+	tcell.Screen
+	// bg is the background of the board.
+	bg tcell.Color
+}
+
+// mono returns style without colors.
+func (s *monoScreen) mono(style tcell.Style) tcell.Style {
+	_, bg, attrs := style.Decompose()
+	if bg != s.bg && bg != tcell.ColorDefault && bg != tcell.ColorReset {
+		attrs |= tcell.AttrReverse
+	}
+	return tcell.StyleDefault.Attributes(attrs)
+}
+
+func (s *monoScreen) SetStyle(style tcell.Style) {
+	_, s.bg, _ = style.Decompose()
+	s.Screen.SetStyle(s.mono(style))
+}
+
+func (s *monoScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	s.Screen.SetContent(x, y, mainc, combc, s.mono(style))
+}
+
+func (s *monoScreen) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	s.Screen.SetCell(x, y, s.mono(style), ch...)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// palette replaces the colors of a theme that tell things apart by hue, so
+// they stay distinct for people with a color vision deficiency.
+type palette struct {
+	name string
+	// age and heat replace the gradients of the theme.
+	age, heat gradient
+	// states, birth, death and ghost replace the colors of the theme.
+	states              []tcell.Color
+	birth, death, ghost tcell.Color
+}
+
+func hex(v int32) tcell.Color {
+	return tcell.NewHexColor(v)
+}
+
+// redGreen is safe for protanopia and deuteranopia. It takes the colors of the
+// Okabe-Ito palette and the cividis gradient, that change from blue to yellow.
+// See: https://jfly.uni-koeln.de/color/
+var redGreen = palette{
+	age:    gradient{hex(0xffea46), hex(0xcbba69), hex(0x7c7b78), hex(0x00204d)},
+	heat:   gradient{hex(0x00204d), hex(0x414d6b), hex(0x7c7b78), hex(0xbcaf6f)},
+	states: []tcell.Color{hex(0xe69f00), hex(0x56b4e9), hex(0x0072b2), hex(0xffffff)},
+	birth:  hex(0x56b4e9),
+	death:  hex(0xe69f00),
+	ghost:  hex(0xffffff),
+}
+
+// palettes holds the palettes selected with -palette. The first one keeps the
+// colors of the themes.
+var palettes = []*palette{
+	{name: "default"},
+	withName(redGreen, "protanopia"),
+	withName(redGreen, "deuteranopia"),
+	{
+		// Tritanopia confuses blue with green and yellow with violet, so the
+		// colors go from red to cyan.
+		name:   "tritanopia",
+		age:    gradient{hex(0xffffff), hex(0xff8c8c), hex(0xc80032), hex(0x500014)},
+		heat:   gradient{hex(0x002828), hex(0x8c1e32), hex(0xb41e3c)},
+		states: []tcell.Color{hex(0xe6002e), hex(0x00c2c2), hex(0xffffff), hex(0x7a0020)},
+		birth:  hex(0x00c2c2),
+		death:  hex(0xe6002e),
+		ghost:  hex(0xff8cff),
+	},
+}
+
+func withName(p palette, name string) *palette {
+	p.name = name
+	return &p
+}
+
+// findPalette returns the palette with the given name.
+func findPalette(name string) (*palette, error) {
+	for _, p := range palettes {
+		if p.name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown palette: %s", name)
+}
+
+// apply returns a copy of t with the colors of the palette.
+func (p *palette) apply(t *theme) *theme {
+	result := *t
+	if p.age != nil {
+		result.age = p.age
+	}
+	if p.heat != nil {
+		result.heat = p.heat
+	}
+	if p.states != nil {
+		result.states = p.states
+	}
+	if p.birth != tcell.ColorDefault {
+		result.birth, result.death, result.ghost = p.birth, p.death, p.ghost
+	}
+	return &result
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// cvdMatrices hold the simulations of the complete color vision deficiencies
+// in linear RGB by Machado, Oliveira and Fernandes (2009).
+// See: https://www.inf.ufrgs.br/~oliveira/pubs_files/CVD_Simulation/CVD_Simulation.html
+var cvdMatrices = map[string][3][3]float64{
+	"protanopia": {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	"deuteranopia": {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	"tritanopia": {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+type lab struct{ l, a, b float64 }
+
+func toLinear(v int32) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// simulate returns the CIELAB color seen with the given deficiency, or with
+// normal vision if the name is empty.
+func simulate(c tcell.Color, deficiency string) lab {
+	r, g, b := c.RGB()
+	rgb := [3]float64{toLinear(r), toLinear(g), toLinear(b)}
+	if m, ok := cvdMatrices[deficiency]; ok {
+		var out [3]float64
+		for i := range out {
+			for j := range rgb {
+				out[i] += m[i][j] * rgb[j]
+			}
+			out[i] = math.Max(0, math.Min(1, out[i]))
+		}
+		rgb = out
+	}
+	// Linear sRGB to XYZ with the D65 white point, and XYZ to CIELAB.
+	x := (0.4124*rgb[0] + 0.3576*rgb[1] + 0.1805*rgb[2]) / 0.95047
+	y := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	z := (0.0193*rgb[0] + 0.1192*rgb[1] + 0.9505*rgb[2]) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	return lab{116*f(y) - 16, 500 * (f(x) - f(y)), 200 * (f(y) - f(z))}
+}
+
+// distance returns the CIE76 color difference.
+func distance(a, b lab) float64 {
+	return math.Sqrt((a.l-b.l)*(a.l-b.l) + (a.a-b.a)*(a.a-b.a) + (a.b-b.b)*(a.b-b.b))
+}
+
+// minDistance is the color difference above which two colors are told apart
+// at a glance.
+const minDistance = 20
+
+func TestSimulationConfusesRedAndGreen(t *testing.T) {
+	red, green := tcell.NewRGBColor(200, 40, 40), tcell.NewRGBColor(40, 160, 40)
+	normal := distance(simulate(red, ""), simulate(green, ""))
+	for _, d := range []string{"protanopia", "deuteranopia"} {
+		if got := distance(simulate(red, d), simulate(green, d)); got > normal/2 {
+			t.Errorf("%s: red and green differ by %.1f, with normal vision by %.1f", d, got, normal)
+		}
+	}
+}
+
+func TestPalettesStayDistinct(t *testing.T) {
+	for _, p := range palettes[1:] {
+		t.Run(p.name, func(t *testing.T) {
+			check := func(what string, a, b tcell.Color) {
+				if d := distance(simulate(a, p.name), simulate(b, p.name)); d < minDistance {
+					t.Errorf("%s differ by %.1f", what, d)
+				}
+			}
+			check("birth and death", p.birth, p.death)
+			check("ghost and birth", p.ghost, p.birth)
+			check("ghost and death", p.ghost, p.death)
+			for i := range p.states {
+				for j := i + 1; j < len(p.states); j++ {
+					check("states", p.states[i], p.states[j])
+				}
+			}
+		})
+	}
+}
+
+func TestPaletteGradientsAreMonotonic(t *testing.T) {
+	for _, p := range palettes[1:] {
+		t.Run(p.name, func(t *testing.T) {
+			for name, gr := range map[string]gradient{"age": p.age, "heat": p.heat} {
+				// The lightness alone must order the colors, as it does
+				// not depend on the hues that are confused.
+				sign := 0.0
+				for i := 1; i < len(gr); i++ {
+					d := simulate(gr[i], p.name).l - simulate(gr[i-1], p.name).l
+					if math.Abs(d) < 5 || (sign != 0 && math.Signbit(d) != math.Signbit(sign)) {
+						t.Errorf("%s: lightness changes by %.1f between colors %d and %d", name, d, i-1, i)
+					}
+					sign = d
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPalette(t *testing.T) {
+	deuteranopia, _ := findPalette("deuteranopia")
+	th := deuteranopia.apply(themes[0])
+	if th == themes[0] || th.name != themes[0].name {
+		t.Fatal("apply must return a renamed copy")
+	}
+	if th.birth != redGreen.birth || themes[0].birth == redGreen.birth {
+		t.Error("apply must replace the colors")
+	}
+	if def, _ := findPalette("default"); def.apply(themes[0]).birth != themes[0].birth {
+		t.Error("the default palette must keep the colors")
+	}
+}
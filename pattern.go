@@ -0,0 +1,177 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+	"golang.org/x/exp/slices"
+)
+
+//go:embed patterns/*.cells
+var patternFiles embed.FS
+
+// library holds the embedded patterns, and the ones of the user pattern
+// directory, sorted by name.
+var library = loadLibrary()
+
+func loadLibrary() []*life.Pattern {
+	entries, err := patternFiles.ReadDir("patterns")
+	if err != nil {
+		panic(err)
+	}
+	var result []*life.Pattern
+	for _, e := range entries {
+		data, err := patternFiles.ReadFile(path.Join("patterns", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		p, err := life.ParsePlaintextPattern(data)
+		if err != nil {
+			panic(fmt.Errorf("%s: %w", e.Name(), err))
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(e.Name(), ".cells")
+		}
+		result = append(result, p)
+	}
+	sortPatterns(result)
+	return result
+}
+
+func sortPatterns(patterns []*life.Pattern) {
+	slices.SortFunc(patterns, func(a, b *life.Pattern) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// userPatternDir returns the default location of the user pattern directory.
+func userPatternDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go_life", "patterns")
+}
+
+// loadUserPatterns adds the patterns of the plaintext, RLE and Life 1.05/1.06
+// files of dir to the library. A missing directory is not an error.
+func loadUserPatterns(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !patternExts[filepath.Ext(e.Name())] {
+			continue
+		}
+		p, err := readPattern(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		library = append(library, p)
+	}
+	sortPatterns(library)
+	return nil
+}
+
+// findPattern returns the library pattern with the given name, ignoring case.
+func findPattern(name string) (*life.Pattern, bool) {
+	for _, p := range library {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// libraryPattern returns the library pattern with the given name, ignoring
+// case, in which underscores stand for the spaces and other characters of the
+// names, as completed by the shells.
+func libraryPattern(name string) (*life.Pattern, bool) {
+	if p, ok := findPattern(name); ok {
+		return p, true
+	}
+	for _, p := range library {
+		if strings.EqualFold(completionWord(p.Name), name) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// patternExts holds the extensions of the pattern files read from the user
+// pattern directory.
+var patternExts = map[string]bool{".cells": true, ".rle": true, ".lif": true, ".life": true}
+
+// readPattern reads a pattern file, in the RLE format if its extension is .rle,
+// in the Life 1.05 or 1.06 format if it is .lif or .life, or in the plaintext
+// format otherwise.
+func readPattern(name string) (*life.Pattern, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	ext := filepath.Ext(name)
+	parse := life.ParsePlaintextPattern
+	switch ext {
+	case ".rle":
+		parse = life.ParseRLEPattern
+	case ".lif", ".life":
+		parse = life.ParseLifePattern
+	}
+	p, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(name), ext)
+	}
+	return p, nil
+}
+
+// readField reads a pattern file like readPattern, returning its board and its
+// name. The board of a multi-state RLE file keeps its dying cells, which the
+// patterns drop.
+func readField(name string) (*life.Field, string, error) {
+	ext := filepath.Ext(name)
+	if ext != ".rle" {
+		p, err := readPattern(name)
+		if err != nil {
+			return nil, "", err
+		}
+		return p.Field(), p.Name, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, "", err
+	}
+	f, title, err := life.ParseRLE(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", name, err)
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(name), ext)
+	}
+	return f, title, nil
+}
+
+// cutPrefix returns s without the provided leading prefix string and reports
+// whether it found the prefix.
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
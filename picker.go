@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// picker is a menu to choose a pattern of the library by name.
+type picker struct {
+	query string
+	// matches holds the library indexes of the patterns matching the query,
+	// best first.
+	matches []int
+	// index is the position in matches of the highlighted pattern.
+	index int
+}
+
+// fuzzyScore reports whether the runes of query appear in name in the same
+// order, ignoring case, and returns how good the match is. Lower scores are
+// better: every rune skipped between the matched ones costs one point.
+func fuzzyScore(name, query string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	score, start := 0, -1
+	i := 0
+	for j, r := range []rune(strings.ToLower(name)) {
+		if i == len(q) {
+			break
+		}
+		if r == q[i] {
+			if start >= 0 {
+				score += j - start - 1
+			}
+			start = j
+			i++
+		}
+	}
+	return score, i == len(q)
+}
+
+// filter updates the matches of the query.
+func (p *picker) filter() {
+	p.matches = p.matches[:0]
+	scores := make(map[int]int)
+	for i, pat := range library {
+		if score, ok := fuzzyScore(pat.Name, p.query); ok {
+			p.matches = append(p.matches, i)
+			scores[i] = score
+		}
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		return scores[p.matches[a]] < scores[p.matches[b]]
+	})
+	p.index = 0
+}
+
+// openPicker shows the pattern picker.
+func (g *game) openPicker() {
+	g.picker = &picker{}
+	g.picker.filter()
+	g.draw()
+}
+
+// pickerKey handles the keys while the pattern picker is shown and reports
+// whether the key was consumed. Typing filters the patterns, the arrows move
+// through them and Enter stamps the highlighted one.
+func (g *game) pickerKey(event *tcell.EventKey) bool {
+	p := g.picker
+	if p == nil {
+		return false
+	}
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.picker = nil
+	case tcell.KeyEnter:
+		if len(p.matches) > 0 {
+			g.selected = p.matches[p.index]
+			g.stamp = library[g.selected]
+		}
+		g.picker = nil
+	case tcell.KeyUp:
+		if p.index > 0 {
+			p.index--
+		}
+	case tcell.KeyDown:
+		if p.index < len(p.matches)-1 {
+			p.index++
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if r := []rune(p.query); len(r) > 0 {
+			p.query = string(r[:len(r)-1])
+			p.filter()
+		}
+	case tcell.KeyRune:
+		if unicode.IsPrint(event.Rune()) {
+			p.query += string(event.Rune())
+			p.filter()
+		}
+	}
+	g.screen.Clear()
+	g.draw()
+	return true
+}
+
+// drawPicker draws the pattern picker in a box centered on the board, with the
+// matching patterns on the left and a preview of the highlighted one on the
+// right.
+func (g *game) drawPicker(cols, rows int) {
+	p := g.picker
+	w, h := 64, 20
+	if w > cols {
+		w = cols
+	}
+	if h > rows {
+		h = rows
+	}
+	x0, y0 := (cols-w)/2, (rows-h)/2
+	style := g.theme.status
+	for y := y0; y < y0+h; y++ {
+		g.drawText(x0, y, x0+w, style, "")
+	}
+	g.drawText(x0, y0, x0+w, style, " Pattern: "+p.query+"_")
+
+	// The list scrolls to keep the highlighted pattern visible.
+	listW, listH := w/2, h-2
+	first := 0
+	if p.index >= listH {
+		first = p.index - listH + 1
+	}
+	for i := 0; i < listH && first+i < len(p.matches); i++ {
+		s := style
+		if first+i == p.index {
+			s = g.theme.cursor
+		}
+		g.drawText(x0+1, y0+2+i, x0+listW, s, library[p.matches[first+i]].Name)
+	}
+	if len(p.matches) == 0 {
+		return
+	}
+	f := library[p.matches[p.index]].Field()
+	g.drawText(x0+listW+1, y0+2, x0+w, style, fmt.Sprintf("%dx%d", f.Width(), f.Height()))
+	g.drawThumbnail(x0+listW+1, y0+3, w-listW-2, h-4, style, f)
+}
+
+// drawThumbnail draws f with braille dots inside the given region, scaling it
+// down to fit.
+func (g *game) drawThumbnail(x0, y0, w, h int, style tcell.Style, f *life.Field) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	const dw, dh = 2, 4
+	// scale is the number of cells of every dot along each axis.
+	scale := 1
+	for int(f.Width()) > w*dw*scale || int(f.Height()) > h*dh*scale {
+		scale++
+	}
+	for y := 0; y < h && y*dh*scale < int(f.Height()); y++ {
+		for x := 0; x < w && x*dw*scale < int(f.Width()); x++ {
+			var mask uint
+			for dy := 0; dy < dh; dy++ {
+				for dx := 0; dx < dw; dx++ {
+					if anyAlive(f, (x*dw+dx)*scale, (y*dh+dy)*scale, scale) {
+						mask |= 1 << (dy*dw + dx)
+					}
+				}
+			}
+			g.screen.SetCell(x0+x, y0+y, style, rendererBraille.glyph(mask))
+		}
+	}
+}
+
+// anyAlive reports whether any cell of the square of the given size at x, y of
+// f is alive.
+func anyAlive(f *life.Field, x, y, size int) bool {
+	for j := y; j < y+size && j < int(f.Height()); j++ {
+		for i := x; i < x+size && i < int(f.Width()); i++ {
+			if f.Alive(i, j) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package life
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// benchSizes and benchDensities hold the boards of the benchmarks, from the
+// size of a small terminal to boards far beyond the screen.
+var (
+	benchSizes     = []uint{64, 256, 1024}
+	benchDensities = []float64{0.1, 0.5}
+)
+
+// benchLife returns a board of the given size with a random soup, the same on
+// every run.
+func benchLife(size uint, density float64) *Life {
+	l := NewLife([]uint{3}, []uint{2, 3}, size, size, 0)
+	l.Field().Randomize(rand.New(rand.NewSource(1)), density)
+	return l
+}
+
+func BenchmarkStep(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, density := range benchDensities {
+			b.Run(fmt.Sprintf("%dx%d/density=%g", size, size, density), func(b *testing.B) {
+				l := benchLife(size, density)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					l.Step()
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkNext counts the neighbors of every cell, without updating the
+// board.
+func BenchmarkNext(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			l := benchLife(size, 0.5)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for y := uint(0); y < size; y++ {
+					for x := uint(0); x < size; x++ {
+						l.Next(x, y)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseRLE(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			l := benchLife(size, 0.5)
+			var data bytes.Buffer
+			if err := WriteRLE(&data, "soup", l.Rule().String(), l.a); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(data.Len()))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ParseRLE(data.Bytes())
+			}
+		})
+	}
+}
+
+func BenchmarkParsePlaintext(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			l := benchLife(size, 0.5)
+			var data bytes.Buffer
+			if err := WritePlaintext(&data, "soup", l.a); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(data.Len()))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ParsePlaintext(data.Bytes())
+			}
+		})
+	}
+}
+
+func BenchmarkParseRule(b *testing.B) {
+	for _, rule := range []string{"B3/S23", "B3678/S34678", "23/3"} {
+		b.Run(rule, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ParseRule(rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package life implements the engine of go_life: boards of cells on a torus
+// stepped with any Life-like rule, in the B/S or S/B notation, and the RLE and
+// plaintext pattern files.
+//
+// A game starts from a random soup, or from a pattern stamped on an empty
+// board:
+//
+//	birth, survival, err := life.ParseRule("B3/S23")
+//	if err != nil {
+//		return err
+//	}
+//	glider, _, err := life.ParseRLE([]byte("x = 3, y = 3\nbo$2bo$3o!"))
+//	if err != nil {
+//		return err
+//	}
+//	l := life.NewLife(birth, survival, 64, 64, 0)
+//	l.Field().Stamp(glider, 10, 10)
+//	for i := 0; i < 100; i++ {
+//		l.Step()
+//	}
+//	fmt.Println(l.Field().Population())
+package life
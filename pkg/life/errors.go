@@ -0,0 +1,20 @@
+package life
+
+import "errors"
+
+// The errors of the parsers, the writers and the checked accessors of the cells wrap
+// these ones, to tell them apart with errors.Is.
+var (
+	// ErrInvalidRule is wrapped by the errors of the rules that cannot be
+	// read.
+	ErrInvalidRule = errors.New("invalid rule")
+	// ErrBadPattern is wrapped by the errors of the pattern files that
+	// cannot be read.
+	ErrBadPattern = errors.New("bad pattern")
+	// ErrOutOfRange is wrapped by the errors of the cells outside the
+	// field.
+	ErrOutOfRange = errors.New("out of range")
+	// ErrDyingStates is wrapped by the errors of the writers of the formats
+	// that cannot hold the dying states of the cells.
+	ErrDyingStates = errors.New("dying states")
+)
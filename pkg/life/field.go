@@ -0,0 +1,454 @@
+package life
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// State is the state of a cell. The rules of two states, like Conway's Game of
+// Life, use Dead and Live only. The ones of the Generations family add the
+// dying states from 2 up, which the cells go through in order after dying,
+// neither counting as neighbors nor coming back to life until they are Dead
+// again.
+type State uint8
+
+const (
+	Dead State = iota
+	Live
+)
+
+// Field represents a two-dimensional field of cells.
+type Field struct {
+	s    [][]State
+	w, h uint
+	// n is the number of live cells, kept up to date by every change of the
+	// cells.
+	n uint
+	// box is the bounding box of the live cells while boxOK. Killing a cell
+	// on its edge may shrink it, so that clears boxOK until the next step or
+	// recount.
+	box   Rect
+	boxOK bool
+}
+
+// NewField returns an empty field of the specified width and height.
+func NewField(w, h uint) *Field {
+	s := make([][]State, h)
+	for i := range s {
+		s[i] = make([]State, w)
+	}
+	return &Field{s: s, w: w, h: h, boxOK: true}
+}
+
+// Width returns the number of columns of the field.
+func (f *Field) Width() uint {
+	return f.w
+}
+
+// Height returns the number of rows of the field.
+func (f *Field) Height() uint {
+	return f.h
+}
+
+// Alive reports whether the specified cell is alive. The coordinates are
+// wrapped toroidally, so an x of -1 is the last column.
+func (f *Field) Alive(x, y int) bool {
+	return f.State(x, y) == Live
+}
+
+// State returns the state of the specified cell, wrapping the coordinates like
+// Alive.
+func (f *Field) State(x, y int) State {
+	return f.s[wrap(y, int(f.h))][wrap(x, int(f.w))]
+}
+
+// Set sets the specified cell alive or dead.
+func (f *Field) Set(x, y uint, b bool) {
+	if b {
+		f.SetState(x, y, Live)
+	} else {
+		f.SetState(x, y, Dead)
+	}
+}
+
+// SetState sets the state of the specified cell.
+func (f *Field) SetState(x, y uint, st State) {
+	was := f.s[y][x]
+	f.s[y][x] = st
+	switch {
+	case was == st:
+	case st == Live:
+		f.n++
+		if f.n == 1 {
+			f.box, f.boxOK = Rect{X: x, Y: y, W: 1, H: 1}, true
+		} else if f.boxOK {
+			f.box = f.box.add(x, y)
+		}
+	case was == Live:
+		f.n--
+		if f.boxOK && (x == f.box.X || y == f.box.Y || x == f.box.X+f.box.W-1 || y == f.box.Y+f.box.H-1) {
+			f.boxOK = false
+		}
+	}
+}
+
+// recount counts the live cells and finds their bounding box again, after the
+// cells were changed without SetState.
+func (f *Field) recount() {
+	f.n = 0
+	f.box, _ = f.scanBox()
+	f.boxOK = true
+	for _, row := range f.s {
+		for _, st := range row {
+			if st == Live {
+				f.n++
+			}
+		}
+	}
+}
+
+// SetChecked sets the specified cell alive or dead like Set, returning an error
+// wrapping ErrOutOfRange instead of panicking when the cell is outside the
+// field, like the positions of the mouse past its edges.
+func (f *Field) SetChecked(x, y int, b bool) error {
+	if x < 0 || y < 0 || x >= int(f.w) || y >= int(f.h) {
+		return fmt.Errorf("%w: cell %d,%d of a %dx%d field", ErrOutOfRange, x, y, f.w, f.h)
+	}
+	f.Set(uint(x), uint(y), b)
+	return nil
+}
+
+// Get reports whether the specified cell is alive, wrapping the coordinates
+// like Alive, since the torus has no edges. Only a field without cells has no
+// cell to wrap to, which returns an error wrapping ErrOutOfRange instead of
+// panicking.
+func (f *Field) Get(x, y int) (bool, error) {
+	if f.w == 0 || f.h == 0 {
+		return false, fmt.Errorf("%w: cell %d,%d of an empty %dx%d field", ErrOutOfRange, x, y, f.w, f.h)
+	}
+	return f.Alive(x, y), nil
+}
+
+// wrap maps v into [0, n), wrapping it toroidally.
+func wrap(v, n int) int {
+	return (v%n + n) % n
+}
+
+// Shift translates all the cells of the field by dx, dy. Cells moving across an
+// edge reappear on the opposite one.
+func (f *Field) Shift(dx, dy int) {
+	s := make([][]State, f.h)
+	for y := range s {
+		row := make([]State, f.w)
+		for x, b := range f.s[wrap(y-dy, int(f.h))] {
+			row[wrap(x+dx, int(f.w))] = b
+		}
+		s[y] = row
+	}
+	f.s = s
+	f.recount()
+}
+
+// Rotate returns a copy of the field rotated 90 degrees clockwise. The width
+// and height of the result are swapped.
+func (f *Field) Rotate() *Field {
+	r := NewField(f.h, f.w)
+	for y := uint(0); y < f.h; y++ {
+		for x := uint(0); x < f.w; x++ {
+			r.s[x][f.h-1-y] = f.s[y][x]
+		}
+	}
+	r.recount()
+	return r
+}
+
+// FlipHorizontal mirrors the field from left to right.
+func (f *Field) FlipHorizontal() {
+	for _, row := range f.s {
+		for i, j := 0, len(row)-1; i < j; i, j = i+1, j-1 {
+			row[i], row[j] = row[j], row[i]
+		}
+	}
+	f.box.X = f.w - f.box.X - f.box.W
+}
+
+// FlipVertical mirrors the field from top to bottom.
+func (f *Field) FlipVertical() {
+	for i, j := 0, len(f.s)-1; i < j; i, j = i+1, j-1 {
+		f.s[i], f.s[j] = f.s[j], f.s[i]
+	}
+	f.box.Y = f.h - f.box.Y - f.box.H
+}
+
+// Rect is a rectangular region of cells.
+type Rect struct {
+	X, Y, W, H uint
+}
+
+// add returns the region grown to contain the cell at x, y.
+func (r Rect) add(x, y uint) Rect {
+	if x < r.X {
+		r.W += r.X - x
+		r.X = x
+	} else if x >= r.X+r.W {
+		r.W = x - r.X + 1
+	}
+	if y < r.Y {
+		r.H += r.Y - y
+		r.Y = y
+	} else if y >= r.Y+r.H {
+		r.H = y - r.Y + 1
+	}
+	return r
+}
+
+// BoundingBox returns the smallest region containing all the live cells. The
+// boolean is false when there are no live cells. It is kept by the changes of
+// the cells, except for the ones killing cells on its edge, which make it scan
+// the field until the next step.
+func (f *Field) BoundingBox() (Rect, bool) {
+	if f.n == 0 {
+		return Rect{}, false
+	}
+	if f.boxOK {
+		return f.box, true
+	}
+	return f.scanBox()
+}
+
+// scanBox finds the bounding box of the live cells, scanning the field.
+func (f *Field) scanBox() (Rect, bool) {
+	minX, minY, maxX, maxY := f.w, f.h, uint(0), uint(0)
+	found := false
+	for y := uint(0); y < f.h; y++ {
+		for x := uint(0); x < f.w; x++ {
+			if f.s[y][x] != Live {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			maxY = y
+			found = true
+		}
+	}
+	if !found {
+		return Rect{}, false
+	}
+	return Rect{X: minX, Y: minY, W: maxX - minX + 1, H: maxY - minY + 1}, true
+}
+
+// Crop returns a copy of the given region of the field.
+func (f *Field) Crop(r Rect) *Field {
+	c := NewField(r.W, r.H)
+	for y := uint(0); y < r.H; y++ {
+		copy(c.s[y], f.s[r.Y+y][r.X:r.X+r.W])
+	}
+	c.recount()
+	return c
+}
+
+// Anchor tells which point of a field stays in place when it is resized.
+type Anchor int
+
+const (
+	TopLeft Anchor = iota
+	Top
+	TopRight
+	Left
+	Center
+	Right
+	BottomLeft
+	Bottom
+	BottomRight
+)
+
+// offset returns the distance the content of a field of size n moves along an
+// axis when the field is resized to m. The position is 0 for the start of the
+// axis, 1 for the middle and 2 for the end.
+func offset(n, m uint, position int) int {
+	return (int(m) - int(n)) * position / 2
+}
+
+// Resize returns a copy of the field with the given size. The cells outside the
+// new size are lost and the new cells are dead.
+func (f *Field) Resize(w, h uint, a Anchor) *Field {
+	r := NewField(w, h)
+	ox, oy := offset(f.w, w, int(a)%3), offset(f.h, h, int(a)/3)
+	for y := 0; y < int(f.h); y++ {
+		ny := y + oy
+		if ny < 0 || ny >= int(h) {
+			continue
+		}
+		for x := 0; x < int(f.w); x++ {
+			if nx := x + ox; nx >= 0 && nx < int(w) {
+				r.s[ny][nx] = f.s[y][x]
+			}
+		}
+	}
+	r.recount()
+	return r
+}
+
+// Grow returns a copy of the field with dw more columns and dh more rows.
+// Negative values shrink it.
+func (f *Field) Grow(dw, dh int, a Anchor) *Field {
+	w, h := int(f.w)+dw, int(f.h)+dh
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	return f.Resize(uint(w), uint(h), a)
+}
+
+// Copy returns a copy of the field.
+func (f *Field) Copy() *Field {
+	return f.Crop(Rect{W: f.w, H: f.h})
+}
+
+// CopyFrom replaces the cells of the field with the ones of src, keeping their
+// states, to reuse the field instead of allocating a copy. A src of another
+// size returns an error wrapping ErrOutOfRange and leaves the field unchanged.
+func (f *Field) CopyFrom(src *Field) error {
+	if f.w != src.w || f.h != src.h {
+		return fmt.Errorf("%w: copying a %dx%d field into a %dx%d one", ErrOutOfRange, src.w, src.h, f.w, f.h)
+	}
+	for y, row := range src.s {
+		copy(f.s[y], row)
+	}
+	f.n, f.box, f.boxOK = src.n, src.box, src.boxOK
+	return nil
+}
+
+// Stamp sets the live cells of p onto the field, with the top-left corner of p
+// at x, y. Cells falling outside the field wrap around the edges.
+func (f *Field) Stamp(p *Field, x, y int) {
+	p.ForEachAlive(func(px, py int) {
+		f.SetState(uint(wrap(x+px, int(f.w))), uint(wrap(y+py, int(f.h))), Live)
+	})
+}
+
+// ForEachAlive calls fn with the position of every live cell, row by row, so
+// the callers interested in the live cells only do not test every cell.
+func (f *Field) ForEachAlive(fn func(x, y int)) {
+	for y, row := range f.s {
+		for x, st := range row {
+			if st == Live {
+				fn(x, y)
+			}
+		}
+	}
+}
+
+// CellChange is a cell that differs between two fields, with its state in the
+// second one.
+type CellChange struct {
+	X, Y  int
+	Alive bool
+	State State
+}
+
+// Diff returns the cells differing between f and other, row by row, with their
+// state in other, so applying them to f gives other. When the sizes differ,
+// the cells outside one of the fields count as dead.
+func (f *Field) Diff(other *Field) []CellChange {
+	var changes []CellChange
+	h := len(f.s)
+	if len(other.s) > h {
+		h = len(other.s)
+	}
+	for y := 0; y < h; y++ {
+		var a, b []State
+		if y < len(f.s) {
+			a = f.s[y]
+		}
+		if y < len(other.s) {
+			b = other.s[y]
+		}
+		w := len(a)
+		if len(b) > w {
+			w = len(b)
+		}
+		for x := 0; x < w; x++ {
+			was, is := Dead, Dead
+			if x < len(a) {
+				was = a[x]
+			}
+			if x < len(b) {
+				is = b[x]
+			}
+			if was != is {
+				changes = append(changes, CellChange{X: x, Y: y, Alive: is == Live, State: is})
+			}
+		}
+	}
+	return changes
+}
+
+// Population returns the number of live cells, kept by the changes of the
+// cells.
+func (f *Field) Population() uint {
+	return f.n
+}
+
+// Hash returns a hash of the cells of the field, the same for the fields of the
+// same size with the same states.
+func (f *Field) Hash() uint64 {
+	h := fnv.New64a()
+	// Every row takes whole bytes, eight cells each.
+	buf := make([]byte, (f.w+7)/8)
+	dying := false
+	for _, row := range f.s {
+		for i := range buf {
+			buf[i] = 0
+		}
+		for x, st := range row {
+			switch {
+			case st == Live:
+				buf[x/8] |= 1 << (x % 8)
+			case st != Dead:
+				dying = true
+			}
+		}
+		h.Write(buf)
+	}
+	// The dying states are hashed after the live cells, so the hashes of the
+	// fields of two states stay the same as before they existed.
+	if dying {
+		for _, row := range f.s {
+			for _, st := range row {
+				h.Write([]byte{byte(st)})
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+// Clear kills all the cells of the field.
+func (f *Field) Clear() {
+	for _, row := range f.s {
+		for x := range row {
+			row[x] = Dead
+		}
+	}
+	f.n, f.boxOK = 0, true
+}
+
+// Randomize replaces the cells of the field with a random soup drawn from r, so
+// the same seed gives the same soup. Up to maxDensity of the cells are set
+// alive.
+func (f *Field) Randomize(r *rand.Rand, maxDensity float64) {
+	f.Clear()
+	for i := uint(0); i < uint(float64(f.w*f.h)*maxDensity); i++ {
+		f.Set(uint(r.Intn(int(f.w))), uint(r.Intn(int(f.h))), true)
+	}
+}
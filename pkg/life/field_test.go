@@ -0,0 +1,146 @@
+package life
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestSetChecked(t *testing.T) {
+	f := NewField(4, 3)
+	for _, c := range [][2]int{{-1, 0}, {0, -1}, {4, 0}, {0, 3}, {4, 3}} {
+		if err := f.SetChecked(c[0], c[1], true); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%v: got %v, want out of range", c, err)
+		}
+	}
+	if f.Population() != 0 {
+		t.Errorf("population %d after the cells out of range, want 0", f.Population())
+	}
+	if err := f.SetChecked(3, 2, true); err != nil || !f.Alive(3, 2) {
+		t.Errorf("3,2: %v, alive %v", err, f.Alive(3, 2))
+	}
+}
+
+func TestGet(t *testing.T) {
+	f := NewField(4, 3)
+	f.Set(3, 2, true)
+	// The coordinates wrap around the torus.
+	for _, c := range [][2]int{{3, 2}, {-1, -1}, {7, 5}} {
+		if alive, err := f.Get(c[0], c[1]); err != nil || !alive {
+			t.Errorf("%v: %v, %v, want alive", c, alive, err)
+		}
+	}
+	if _, err := NewField(0, 3).Get(0, 0); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("empty field: got %v, want out of range", err)
+	}
+}
+
+func TestResize(t *testing.T) {
+	// The cells are told apart by their states, so a moved cell shows.
+	src := []string{"O23", "456", "789"}
+	cases := []struct {
+		w, h uint
+		a    Anchor
+		want []string
+	}{
+		{5, 5, TopLeft, []string{"O23..", "456..", "789..", ".....", "....."}},
+		{5, 5, Top, []string{".O23.", ".456.", ".789.", ".....", "....."}},
+		{5, 5, TopRight, []string{"..O23", "..456", "..789", ".....", "....."}},
+		{5, 5, Left, []string{".....", "O23..", "456..", "789..", "....."}},
+		{5, 5, Center, []string{".....", ".O23.", ".456.", ".789.", "....."}},
+		{5, 5, Right, []string{".....", "..O23", "..456", "..789", "....."}},
+		{5, 5, BottomLeft, []string{".....", ".....", "O23..", "456..", "789.."}},
+		{5, 5, Bottom, []string{".....", ".....", ".O23.", ".456.", ".789."}},
+		{5, 5, BottomRight, []string{".....", ".....", "..O23", "..456", "..789"}},
+		// An odd difference leaves the extra cell after the content.
+		{4, 6, Center, []string{"....", "O23.", "456.", "789.", "....", "...."}},
+		{1, 1, TopLeft, []string{"O"}},
+		{1, 1, Center, []string{"5"}},
+		{1, 1, BottomRight, []string{"9"}},
+		{2, 2, Center, []string{"O2", "45"}},
+		{2, 2, Bottom, []string{"45", "78"}},
+		{2, 2, BottomRight, []string{"56", "89"}},
+		{4, 2, Right, []string{".O23", ".456"}},
+		{0, 2, Center, []string{"", ""}},
+	}
+	for _, c := range cases {
+		f := stateField(src)
+		got := f.Resize(c.w, c.h, c.a)
+		if want := stateField(c.want); !equalFields(got, want) {
+			t.Errorf("%dx%d anchored at %d: got %v, want %v", c.w, c.h, c.a, got.s, want.s)
+		}
+		if want := stateField(c.want).Population(); got.Population() != want {
+			t.Errorf("%dx%d anchored at %d: population %d, want %d", c.w, c.h, c.a, got.Population(), want)
+		}
+		if !equalFields(f, stateField(src)) {
+			t.Errorf("%dx%d anchored at %d: the source changed", c.w, c.h, c.a)
+		}
+	}
+}
+
+func TestGrow(t *testing.T) {
+	src := []string{"O23", "456", "789"}
+	cases := []struct {
+		dw, dh int
+		a      Anchor
+		want   []string
+	}{
+		{0, 0, Center, src},
+		{2, 0, Center, []string{".O23.", ".456.", ".789."}},
+		{1, 1, BottomRight, []string{"....", ".O23", ".456", ".789"}},
+		{-1, -2, TopRight, []string{"23"}},
+		{-2, -2, Center, []string{"5"}},
+		// Shrinking past the size leaves an empty field.
+		{-5, 0, Center, []string{"", "", ""}},
+		{-5, -5, Center, nil},
+	}
+	for _, c := range cases {
+		got := stateField(src).Grow(c.dw, c.dh, c.a)
+		var want *Field
+		if len(c.want) == 0 {
+			want = NewField(0, 0)
+		} else {
+			want = stateField(c.want)
+		}
+		if !equalFields(got, want) {
+			t.Errorf("%+d%+d anchored at %d: got %v, want %v", c.dw, c.dh, c.a, got.s, want.s)
+		}
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	src := stateField([]string{"O2.", ".3O"})
+	f := stateField([]string{"OOO", "OOO"})
+	if err := f.CopyFrom(src); err != nil {
+		t.Fatal(err)
+	}
+	if !equalFields(f, src) || f.Population() != 2 {
+		t.Errorf("got %v with population %d, want %v", f.s, f.Population(), src.s)
+	}
+	if box, ok := f.BoundingBox(); !ok || box != (Rect{X: 0, Y: 0, W: 3, H: 2}) {
+		t.Errorf("bounding box %v, %v", box, ok)
+	}
+	if err := f.CopyFrom(NewField(2, 2)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("got %v, want out of range", err)
+	}
+	if !equalFields(f, src) {
+		t.Error("the field changed after the failed copy")
+	}
+}
+
+func TestRandomize(t *testing.T) {
+	soup := func(seed int64) uint64 {
+		f := NewField(32, 16)
+		f.Randomize(rand.New(rand.NewSource(seed)), 0.5)
+		return f.Hash()
+	}
+	a := soup(7)
+	// The soups do not depend on the global generator.
+	rand.Int()
+	if soup(7) != a {
+		t.Error("the same seed gives another soup")
+	}
+	if soup(8) == a {
+		t.Error("another seed gives the same soup")
+	}
+}
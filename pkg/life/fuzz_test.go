@@ -0,0 +1,161 @@
+package life
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// The fuzz targets check that the parsers return errors wrapping ErrInvalidRule
+// or ErrBadPattern instead of panicking, and that what they read is written
+// back the same. Run them with:
+//
+//	go test ./pkg/life -run NONE -fuzz FuzzParseRLE -fuzztime 1m
+
+// libraryFields returns the patterns of the library of the game, read from the
+// patterns directory at the root of the repository.
+func libraryFields(tb testing.TB) []*Field {
+	tb.Helper()
+	paths, err := filepath.Glob(filepath.Join("..", "..", "patterns", "*.cells"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var fields []*Field
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		f, _, err := ParsePlaintext(data)
+		if err != nil {
+			tb.Fatalf("%s: %v", path, err)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func FuzzParseRule(f *testing.F) {
+	for _, s := range []string{"B3/S23", "b36/s23", "B3678/S34678", "23/3", "/2", "B2/S", "B9/S23", "B2/S/C3", "345/2/4", "B2/S/C1", "x", ""} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		birth, survival, states, err := ParseRuleStates(s)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidRule) {
+				t.Fatalf("%q: %v does not wrap ErrInvalidRule", s, err)
+			}
+			return
+		}
+		for _, counts := range [][]uint{birth, survival} {
+			if !slices.IsSorted(counts) || len(counts) > 0 && counts[len(counts)-1] > 8 {
+				t.Fatalf("%q: invalid neighbor counts %v", s, counts)
+			}
+		}
+		l := NewLife(birth, survival, 1, 1, 0)
+		l.SetStates(states)
+		rule := l.Rule().String()
+		b, s2, c, err := ParseRuleStates(rule)
+		if err != nil || !reflect.DeepEqual(b, birth) || !reflect.DeepEqual(s2, survival) || c != states {
+			t.Fatalf("%q: %s reads back as %v/%v/%d, %v", s, rule, b, s2, c, err)
+		}
+	})
+}
+
+func FuzzParseRLE(f *testing.F) {
+	for _, field := range libraryFields(f) {
+		var b bytes.Buffer
+		if err := WriteRLE(&b, "seed", "B3/S23", field); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b.Bytes())
+	}
+	for _, s := range []string{"x = 3, y = 1\n3o!", "x = -1, y = 2\n", "x = 0, y = 0\n!", "#N x\nx=2\n2$2o\n", "x = 1\n99999999999o!", "bo$"} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, _, err := ParseRLE(data)
+		if err != nil {
+			if !errors.Is(err, ErrBadPattern) {
+				t.Fatalf("%q: %v does not wrap ErrBadPattern", data, err)
+			}
+			return
+		}
+		var b bytes.Buffer
+		if err := WriteRLE(&b, "fuzz", "B3/S23", p); err != nil {
+			t.Fatal(err)
+		}
+		q, _, err := ParseRLE(b.Bytes())
+		if err != nil {
+			t.Fatalf("%q: written as %q, which reads back with %v", data, b.String(), err)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Fatalf("%q: written as %q, which reads back different", data, b.String())
+		}
+	})
+}
+
+func FuzzParsePlaintext(f *testing.F) {
+	for _, field := range libraryFields(f) {
+		var b bytes.Buffer
+		if err := WritePlaintext(&b, "seed", field); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b.Bytes())
+	}
+	for _, s := range []string{"!Name: x\n.O\nO.\n", "..\r\n*\n", "O#\n", "\n\n", "!"} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, _, err := ParsePlaintext(data)
+		if err != nil {
+			if !errors.Is(err, ErrBadPattern) {
+				t.Fatalf("%q: %v does not wrap ErrBadPattern", data, err)
+			}
+			return
+		}
+		var b bytes.Buffer
+		if err := WritePlaintext(&b, "fuzz", p); err != nil {
+			t.Fatal(err)
+		}
+		q, _, err := ParsePlaintext(b.Bytes())
+		if err != nil {
+			t.Fatalf("%q: written as %q, which reads back with %v", data, b.String(), err)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Fatalf("%q: written as %q, which reads back different", data, b.String())
+		}
+	})
+}
+
+func FuzzParseLife(f *testing.F) {
+	for _, field := range libraryFields(f) {
+		var b bytes.Buffer
+		b.WriteString("#Life 1.06\n")
+		field.ForEachAlive(func(x, y int) {
+			fmt.Fprintf(&b, "%d %d\n", x, y)
+		})
+		f.Add(b.Bytes())
+	}
+	for _, s := range []string{"#Life 1.05\n#N\n#P -1 -1\n.*\n..*\n***\n", "#Life 1.05\n#P 1\n*", "#Life 1.06\n-2147483648 2147483647\n", "#Life 1.06\n0 0 0\n", "#Life"} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, _, err := ParseLife(data)
+		if err != nil {
+			if !errors.Is(err, ErrBadPattern) {
+				t.Fatalf("%q: %v does not wrap ErrBadPattern", data, err)
+			}
+			return
+		}
+		if box, ok := p.BoundingBox(); ok && (box.X != 0 || box.Y != 0) {
+			t.Fatalf("%q: the cells do not start at the edges", data)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// ParseLife reads a pattern in the Life 1.05 or the Life 1.06 (.lif) format,
+// told apart by their "#Life" header, returning its cells, moved so the
+// top-left live cell is at the edges of the field, and the rule of its "#R" or
+// "#N" line, if any. The errors wrap ErrBadPattern.
+// See: https://conwaylife.com/wiki/Life_1.05 and
+// https://conwaylife.com/wiki/Life_1.06
+func ParseLife(data []byte) (f *Field, rule string, err error) {
+	scanner := newScanner(data)
+	header := ""
+	for header == "" && scanner.Scan() {
+		header = strings.TrimSpace(scanner.Text())
+	}
+	if err := scanError(scanner); err != nil {
+		return nil, "", err
+	}
+	var cells []image.Point
+	switch header {
+	case "#Life 1.05":
+		cells, rule, err = parseLife105(scanner)
+	case "#Life 1.06":
+		cells, err = parseLife106(scanner)
+	default:
+		return nil, "", fmt.Errorf("%w: missing the #Life 1.05 or 1.06 header", ErrBadPattern)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := scanError(scanner); err != nil {
+		return nil, "", err
+	}
+	f, err = cellsField(cells)
+	return f, rule, err
+}
+
+// parseLife105 reads the lines after the header of a Life 1.05 pattern: blocks
+// of rows of dots and stars, each after the "#P" line giving the position of
+// its top-left cell.
+func parseLife105(scanner *bufio.Scanner) (cells []image.Point, rule string, err error) {
+	var x, y int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#P"):
+			fields := strings.Fields(line[2:])
+			if len(fields) != 2 {
+				return nil, "", fmt.Errorf("%w: invalid block position: %s", ErrBadPattern, line)
+			}
+			if x, err = parseCoordinate(fields[0]); err != nil {
+				return nil, "", err
+			}
+			if y, err = parseCoordinate(fields[1]); err != nil {
+				return nil, "", err
+			}
+		case strings.HasPrefix(line, "#N"):
+			rule = "B3/S23"
+		case strings.HasPrefix(line, "#R"):
+			rule = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "#"):
+		default:
+			for i, r := range line {
+				switch r {
+				case '.':
+				case '*':
+					cells = append(cells, image.Pt(x+i, y))
+				default:
+					return nil, "", fmt.Errorf("%w: invalid cell %q", ErrBadPattern, r)
+				}
+			}
+			y++
+		}
+	}
+	return cells, rule, nil
+}
+
+// parseLife106 reads the lines after the header of a Life 1.06 pattern, with
+// the coordinates of a live cell on each.
+func parseLife106(scanner *bufio.Scanner) ([]image.Point, error) {
+	var cells []image.Point
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: invalid cell: %s", ErrBadPattern, line)
+		}
+		x, err := parseCoordinate(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		y, err := parseCoordinate(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, image.Pt(x, y))
+	}
+	return cells, nil
+}
+
+// parseCoordinate reads a coordinate of a cell, which may be negative.
+func parseCoordinate(s string) (int, error) {
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid coordinate: %s", ErrBadPattern, s)
+	}
+	return int(n), nil
+}
+
+// cellsField returns the smallest field holding the live cells, with the
+// top-left one at the edges.
+func cellsField(cells []image.Point) (*Field, error) {
+	if len(cells) == 0 {
+		return NewField(0, 0), nil
+	}
+	box := image.Rectangle{Min: cells[0], Max: cells[0].Add(image.Pt(1, 1))}
+	for _, c := range cells[1:] {
+		box = box.Union(image.Rectangle{Min: c, Max: c.Add(image.Pt(1, 1))})
+	}
+	w, h := int64(box.Dx()), int64(box.Dy())
+	if w > maxPatternCells || h > maxPatternCells || w*h > maxPatternCells {
+		return nil, errTooLarge
+	}
+	f := NewField(uint(w), uint(h))
+	for _, c := range cells {
+		f.Set(uint(c.X-box.Min.X), uint(c.Y-box.Min.Y), true)
+	}
+	return f, nil
+}
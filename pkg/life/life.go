@@ -0,0 +1,328 @@
+package life
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/kerrigan29a/drawille-go"
+	"golang.org/x/exp/slices"
+)
+
+// Life stores the state of a round of Conway's Game of Life.
+type Life struct {
+	a, b            *Field
+	w, h            uint
+	birth, survival []uint
+	// states is the number of states of the rule, 2 unless it has dying
+	// states.
+	states uint8
+	// age holds the number of generations every live cell has been alive.
+	age [][]uint
+	// gen is the number of steps taken.
+	gen       uint64
+	observers []func(gen uint64, stats StepStats)
+}
+
+// StepStats holds the changes of a step and the population it left.
+type StepStats struct {
+	Births, Deaths, Population uint
+}
+
+// NewLife returns a new Life game state with a random initial state, seeded with
+// the current time. A repeatable soup starts from a maxDensity of 0, filling the
+// field with Randomize and a seeded generator.
+func NewLife(birth, survival []uint, w, h uint, maxDensity float64) *Life {
+	a := NewField(w, h)
+	if maxDensity > 0 {
+		a.Randomize(rand.New(rand.NewSource(time.Now().UnixNano())), maxDensity)
+	}
+	return &Life{
+		a:        a,
+		b:        NewField(w, h),
+		w:        w,
+		h:        h,
+		birth:    birth,
+		survival: survival,
+		states:   2,
+		age:      newAges(w, h),
+	}
+}
+
+// Field returns the board of the current generation. Changing it changes the
+// game.
+func (l *Life) Field() *Field {
+	return l.a
+}
+
+// Previous returns the board of the previous generation.
+func (l *Life) Previous() *Field {
+	return l.b
+}
+
+// Width returns the number of columns of the board.
+func (l *Life) Width() uint {
+	return l.w
+}
+
+// Height returns the number of rows of the board.
+func (l *Life) Height() uint {
+	return l.h
+}
+
+// Birth returns the neighbor counts giving birth to dead cells.
+func (l *Life) Birth() []uint {
+	return l.birth
+}
+
+// Survival returns the neighbor counts keeping live cells alive.
+func (l *Life) Survival() []uint {
+	return l.survival
+}
+
+// SetRule changes the rule of the game, with its number of states, taking
+// effect on the next step.
+func (l *Life) SetRule(r Rule) {
+	l.birth, l.survival = r.Birth, r.Survival
+	l.SetStates(r.States)
+}
+
+// States returns the number of states of the rule: 2 for the rules like
+// Conway's Game of Life, more for the ones of the Generations family.
+func (l *Life) States() uint8 {
+	return l.states
+}
+
+// SetStates changes the number of states of the rule, taking effect on the next
+// step. With more than 2, the live cells dying go through the dying states
+// before being dead. Fewer than 2 count as 2.
+func (l *Life) SetStates(n uint8) {
+	if n < 2 {
+		n = 2
+	}
+	l.states = n
+}
+
+func newAges(w, h uint) [][]uint {
+	age := make([][]uint, h)
+	for i := range age {
+		age[i] = make([]uint, w)
+	}
+	return age
+}
+
+// Age returns the number of generations the specified cell has been alive, or
+// 0 if it is dead. Cells set alive by hand have age 1. The coordinates are
+// wrapped like in Alive.
+func (l *Life) Age(x, y int) uint {
+	x, y = wrap(x, int(l.w)), wrap(y, int(l.h))
+	if l.a.s[y][x] != Live {
+		return 0
+	}
+	if a := l.age[y][x]; a > 0 {
+		return a
+	}
+	return 1
+}
+
+// ForEachAlive calls fn with the position of every live cell of the current
+// generation, row by row.
+func (l *Life) ForEachAlive(fn func(x, y int)) {
+	l.a.ForEachAlive(fn)
+}
+
+// Alive reports whether the specified cell is alive.
+// If the x or y coordinates are outside the field boundaries they are wrapped
+// toroidally, however far they are. For instance, an x value of -1 is treated
+// as width-1.
+func (l *Life) Alive(x, y int) bool {
+	return l.a.s[wrap(y, int(l.a.h))][wrap(x, int(l.a.w))] == Live
+}
+
+func contains(x uint, xs []uint) bool {
+	_, ok := slices.BinarySearch(xs, x)
+	return ok
+}
+
+// Next reports whether the specified cell is alive at the next time step.
+func (l *Life) Next(x, y uint) bool {
+	return l.NextState(x, y) == Live
+}
+
+// NextState returns the state of the specified cell at the next time step.
+func (l *Life) NextState(x, y uint) State {
+	st := l.a.s[y][x]
+	if st > Live {
+		// Dying cells go on to the next state, and are dead after the
+		// last one.
+		if st+1 >= State(l.states) {
+			return Dead
+		}
+		return st + 1
+	}
+	// Count the adjacent cells that are alive.
+	neighbors := uint(0)
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if (j != 0 || i != 0) && l.Alive(int(x)+i, int(y)+j) {
+				neighbors++
+			}
+		}
+	}
+	// Return next state according to the game rules:
+	//   dead and neighbors in BIRTH: on,
+	//   alive and neighbors in SURVIVAL: on,
+	//   otherwise: off, or dying if the rule has dying states.
+	if st == Dead && contains(neighbors, l.birth) || st == Live && contains(neighbors, l.survival) {
+		return Live
+	}
+	if st == Live && l.states > 2 {
+		return Live + 1
+	}
+	return Dead
+}
+
+// Changed reports whether the specified cell changed in the last time step.
+// The coordinates are wrapped like in Alive.
+func (l *Life) Changed(x, y int) bool {
+	x, y = wrap(x, int(l.w)), wrap(y, int(l.h))
+	return l.a.s[y][x] != l.b.s[y][x]
+}
+
+// Turnover returns the number of cells born and dead in the last time step.
+func (l *Life) Turnover() (births, deaths uint) {
+	for y, row := range l.a.s {
+		for x, st := range row {
+			if alive, was := st == Live, l.b.s[y][x] == Live; alive != was {
+				if alive {
+					births++
+				} else {
+					deaths++
+				}
+			}
+		}
+	}
+	return births, deaths
+}
+
+// Peek returns the game board of the next time step, without changing the
+// current one.
+func (l *Life) Peek() *Field {
+	f := NewField(l.w, l.h)
+	for y := uint(0); y < l.h; y++ {
+		for x := uint(0); x < l.w; x++ {
+			f.SetState(x, y, l.NextState(x, y))
+		}
+	}
+	return f
+}
+
+// Step advances the game by one instant, recomputing and updating all cells,
+// and then calls the functions added with OnStep.
+func (l *Life) Step() {
+	var stats StepStats
+	var box Rect
+	// Update the state of the next field (b) from the current field (a).
+	for y := uint(0); y < l.h; y++ {
+		for x := uint(0); x < l.w; x++ {
+			st, alive := l.NextState(x, y), l.a.s[y][x] == Live
+			l.b.s[y][x] = st
+			next := st == Live
+			if next {
+				l.age[y][x] = l.Age(int(x), int(y)) + 1
+				if stats.Population == 0 {
+					box = Rect{X: x, Y: y, W: 1, H: 1}
+				} else {
+					box = box.add(x, y)
+				}
+				stats.Population++
+			} else {
+				l.age[y][x] = 0
+			}
+			if next && !alive {
+				stats.Births++
+			} else if !next && alive {
+				stats.Deaths++
+			}
+		}
+	}
+	// The cells of b were written without SetState, so its count and
+	// bounding box are the ones found on the way.
+	l.b.n, l.b.box, l.b.boxOK = stats.Population, box, true
+	// Swap fields a and b.
+	l.a, l.b = l.b, l.a
+	l.gen++
+	for _, fn := range l.observers {
+		fn(l.gen, stats)
+	}
+}
+
+// OnStep adds a function called after every step with the number of steps
+// taken and the changes of the step, in the order the functions were added.
+func (l *Life) OnStep(fn func(gen uint64, stats StepStats)) {
+	l.observers = append(l.observers, fn)
+}
+
+// Population returns the number of live cells of the current generation, kept
+// by the steps and the changes of the cells instead of counted.
+func (l *Life) Population() uint64 {
+	return uint64(l.a.n)
+}
+
+// BoundingBox returns the smallest region containing the live cells of the
+// current generation, found by the steps. The boolean is false when there are
+// no live cells.
+func (l *Life) BoundingBox() (Rect, bool) {
+	return l.a.BoundingBox()
+}
+
+// Generation returns the number of steps taken. Replacing the field does not
+// change it.
+func (l *Life) Generation() uint64 {
+	return l.gen
+}
+
+// Rule returns the rule of the game.
+func (l *Life) Rule() Rule {
+	return Rule{Birth: l.birth, Survival: l.survival, States: l.states}
+}
+
+// Resize changes the size of the game board, keeping the given anchor point in
+// place. New cells are dead.
+func (l *Life) Resize(w, h uint, a Anchor) {
+	l.a = l.a.Resize(w, h, a)
+	l.b = NewField(w, h)
+	l.w, l.h = w, h
+	l.age = newAges(w, h)
+}
+
+// SetField replaces the game board with f, taking its size.
+func (l *Life) SetField(f *Field) {
+	l.a = f
+	l.b = NewField(f.w, f.h)
+	l.w, l.h = f.w, f.h
+	l.age = newAges(f.w, f.h)
+}
+
+// Rotate turns the game board 90 degrees clockwise, swapping its width and
+// height.
+func (l *Life) Rotate() {
+	l.a = l.a.Rotate()
+	l.w, l.h = l.h, l.w
+	l.b = NewField(l.w, l.h)
+	l.age = newAges(l.w, l.h)
+}
+
+// Crop shrinks the game board to the given region.
+func (l *Life) Crop(r Rect) {
+	l.a = l.a.Crop(r)
+	l.b = NewField(r.W, r.H)
+	l.w, l.h = r.W, r.H
+	l.age = newAges(r.W, r.H)
+}
+
+// String returns the game board as a string.
+func (l *Life) String() string {
+	g := drawille.NewCanvas()
+	l.a.ForEachAlive(g.Set)
+	return g.String()
+}
@@ -0,0 +1,266 @@
+package life
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The binary encodings start with a magic string telling the fields from the
+// games and a version byte, bumped whenever the layout changes, so the old
+// save files are still told apart from the new ones.
+const (
+	fieldMagic = "GLF"
+	lifeMagic  = "GLL"
+	// encodingVersion is the version of the binary and JSON encodings
+	// written.
+	encodingVersion = 1
+)
+
+// MarshalBinary encodes the field as its magic and version, its width and
+// height as big endian uint32, and its cells as a bitset, row by row, with the
+// first cell in the lowest bit of the first byte. The dying cells are written
+// dead.
+func (f *Field) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(fieldMagic)+9+int(f.w*f.h+7)/8)
+	data = append(data, fieldMagic...)
+	data = append(data, encodingVersion)
+	return f.appendCells(data), nil
+}
+
+// appendCells appends the size and the bitset of the cells of the field.
+func (f *Field) appendCells(data []byte) []byte {
+	var size [8]byte
+	binary.BigEndian.PutUint32(size[:], uint32(f.w))
+	binary.BigEndian.PutUint32(size[4:], uint32(f.h))
+	data = append(data, size[:]...)
+	bits := make([]byte, (f.w*f.h+7)/8)
+	f.ForEachAlive(func(x, y int) {
+		i := uint(y)*f.w + uint(x)
+		bits[i/8] |= 1 << (i % 8)
+	})
+	return append(data, bits...)
+}
+
+// UnmarshalBinary decodes a field encoded by MarshalBinary, replacing the
+// field. The errors wrap ErrBadPattern.
+func (f *Field) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, fieldMagic)
+	if err != nil {
+		return err
+	}
+	g, rest, err := readCells(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d bytes after the cells", ErrBadPattern, len(rest))
+	}
+	*f = *g
+	return nil
+}
+
+// readHeader checks the magic and the version at the start of data, returning
+// the rest.
+func readHeader(data []byte, magic string) ([]byte, error) {
+	if len(data) < len(magic)+1 || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: missing the %s header", ErrBadPattern, magic)
+	}
+	if v := data[len(magic)]; v != encodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrBadPattern, v)
+	}
+	return data[len(magic)+1:], nil
+}
+
+// readCells decodes the size and the bitset appended by appendCells, returning
+// the field and the bytes after it.
+func readCells(data []byte) (*Field, []byte, error) {
+	if len(data) < 8 {
+		return nil, nil, fmt.Errorf("%w: missing the size", ErrBadPattern)
+	}
+	w, h := uint64(binary.BigEndian.Uint32(data)), uint64(binary.BigEndian.Uint32(data[4:]))
+	data = data[8:]
+	if w > maxPatternCells || h > maxPatternCells || w*h > maxPatternCells {
+		return nil, nil, errTooLarge
+	}
+	n := (w*h + 7) / 8
+	if uint64(len(data)) < n {
+		return nil, nil, fmt.Errorf("%w: %d bytes of cells, want %d", ErrBadPattern, len(data), n)
+	}
+	f := NewField(uint(w), uint(h))
+	for i := uint64(0); i < w*h; i++ {
+		if data[i/8]&(1<<(i%8)) != 0 {
+			f.s[i/w][i%w] = Live
+		}
+	}
+	f.recount()
+	return f, data[n:], nil
+}
+
+// fieldJSON is the JSON encoding of a field, with its rows written like in the
+// plaintext format.
+type fieldJSON struct {
+	Version int      `json:"version"`
+	Width   uint     `json:"width"`
+	Height  uint     `json:"height"`
+	Rows    []string `json:"rows"`
+}
+
+// MarshalJSON encodes the field as an object with its version, its size and
+// its rows, with an O for every live cell and a dot for every dead or dying
+// one.
+func (f *Field) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.toJSON())
+}
+
+func (f *Field) toJSON() fieldJSON {
+	rows := make([]string, f.h)
+	for y, row := range f.s {
+		var b strings.Builder
+		for _, st := range row {
+			if st == Live {
+				b.WriteByte('O')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return fieldJSON{Version: encodingVersion, Width: f.w, Height: f.h, Rows: rows}
+}
+
+// UnmarshalJSON decodes a field encoded by MarshalJSON, replacing the field.
+// The errors of the contents wrap ErrBadPattern.
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var j fieldJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	g, err := j.field()
+	if err != nil {
+		return err
+	}
+	*f = *g
+	return nil
+}
+
+func (j fieldJSON) field() (*Field, error) {
+	if j.Version != encodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrBadPattern, j.Version)
+	}
+	if j.Width > maxPatternCells || j.Height > maxPatternCells || j.Width*j.Height > maxPatternCells {
+		return nil, errTooLarge
+	}
+	if uint(len(j.Rows)) != j.Height {
+		return nil, fmt.Errorf("%w: %d rows, want %d", ErrBadPattern, len(j.Rows), j.Height)
+	}
+	f := NewField(j.Width, j.Height)
+	for y, row := range j.Rows {
+		if uint(len(row)) != j.Width {
+			return nil, fmt.Errorf("%w: row %d has %d cells, want %d", ErrBadPattern, y, len(row), j.Width)
+		}
+		for x, c := range row {
+			switch c {
+			case 'O':
+				f.s[y][x] = Live
+			case '.':
+			default:
+				return nil, fmt.Errorf("%w: unexpected %q in row %d", ErrBadPattern, c, y)
+			}
+		}
+	}
+	f.recount()
+	return f, nil
+}
+
+// MarshalBinary encodes the game as its magic and version, its generation as a
+// big endian uint64, its rule as a length and the B/S notation, and its board
+// like Field.MarshalBinary. The ages of the cells, the dying cells and the
+// previous board are not kept, so the live cells restart with age 1.
+func (l *Life) MarshalBinary() ([]byte, error) {
+	rule := l.Rule().String()
+	data := make([]byte, 0, len(lifeMagic)+18+len(rule)+int(l.w*l.h+7)/8)
+	data = append(data, lifeMagic...)
+	data = append(data, encodingVersion)
+	var gen [8]byte
+	binary.BigEndian.PutUint64(gen[:], l.gen)
+	data = append(data, gen[:]...)
+	data = append(data, byte(len(rule)))
+	data = append(data, rule...)
+	return l.a.appendCells(data), nil
+}
+
+// UnmarshalBinary decodes a game encoded by MarshalBinary, replacing its rule,
+// board and generation. The functions added with OnStep are kept. The errors
+// wrap ErrBadPattern or ErrInvalidRule.
+func (l *Life) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, lifeMagic)
+	if err != nil {
+		return err
+	}
+	if len(data) < 9 || len(data) < 9+int(data[8]) {
+		return fmt.Errorf("%w: missing the generation or the rule", ErrBadPattern)
+	}
+	gen, rule := binary.BigEndian.Uint64(data), string(data[9:9+data[8]])
+	birth, survival, states, err := ParseRuleStates(rule)
+	if err != nil {
+		return err
+	}
+	f, rest, err := readCells(data[9+data[8]:])
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("%w: %d bytes after the cells", ErrBadPattern, len(rest))
+	}
+	l.restore(birth, survival, states, gen, f)
+	return nil
+}
+
+// restore replaces the rule, the generation and the board of the game.
+func (l *Life) restore(birth, survival []uint, states uint8, gen uint64, f *Field) {
+	l.SetField(f)
+	l.SetRule(Rule{Birth: birth, Survival: survival, States: states})
+	l.gen = gen
+}
+
+// lifeJSON is the JSON encoding of a game.
+type lifeJSON struct {
+	Version    int       `json:"version"`
+	Rule       string    `json:"rule"`
+	Generation uint64    `json:"generation"`
+	Field      fieldJSON `json:"field"`
+}
+
+// MarshalJSON encodes the game as an object with its version, its rule in B/S
+// notation, its generation and its board like Field.MarshalJSON.
+func (l *Life) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lifeJSON{
+		Version:    encodingVersion,
+		Rule:       l.Rule().String(),
+		Generation: l.gen,
+		Field:      l.a.toJSON(),
+	})
+}
+
+// UnmarshalJSON decodes a game encoded by MarshalJSON, like UnmarshalBinary.
+func (l *Life) UnmarshalJSON(data []byte) error {
+	var j lifeJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != encodingVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrBadPattern, j.Version)
+	}
+	birth, survival, states, err := ParseRuleStates(j.Rule)
+	if err != nil {
+		return err
+	}
+	f, err := j.Field.field()
+	if err != nil {
+		return err
+	}
+	l.restore(birth, survival, states, j.Generation, f)
+	return nil
+}
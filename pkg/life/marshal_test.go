@@ -0,0 +1,111 @@
+package life
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestFieldMarshalBinary(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for _, size := range propertySizes {
+		f := randomField(r, size[0], size[1])
+		data, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g Field
+		if err := g.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%dx%d: %v", size[0], size[1], err)
+		}
+		if !equalFields(f, &g) {
+			t.Errorf("%dx%d: reads back different", size[0], size[1])
+		}
+	}
+}
+
+func TestFieldMarshalJSON(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for _, size := range propertySizes {
+		f := randomField(r, size[0], size[1])
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g Field
+		if err := json.Unmarshal(data, &g); err != nil {
+			t.Fatalf("%dx%d: %v", size[0], size[1], err)
+		}
+		if !equalFields(f, &g) {
+			t.Errorf("%dx%d: reads back different", size[0], size[1])
+		}
+	}
+}
+
+// TestLifeMarshal checks that a game read back keeps its rule and generation,
+// and goes on like the original one.
+func TestLifeMarshal(t *testing.T) {
+	l := NewLife([]uint{3, 6}, []uint{2, 3}, 33, 17, 0.5)
+	for i := 0; i < 5; i++ {
+		l.Step()
+	}
+	for name, codec := range map[string]struct {
+		marshal   func() ([]byte, error)
+		unmarshal func(*Life, []byte) error
+	}{
+		"binary": {l.MarshalBinary, (*Life).UnmarshalBinary},
+		"json":   {l.MarshalJSON, (*Life).UnmarshalJSON},
+	} {
+		data, err := codec.marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := NewLife(nil, nil, 1, 1, 0)
+		if err := codec.unmarshal(m, data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !m.Rule().Equal(l.Rule()) || m.Generation() != l.Generation() {
+			t.Errorf("%s: %s at generation %d, want %s at %d", name, m.Rule(), m.Generation(), l.Rule(), l.Generation())
+		}
+		if !equalFields(m.Field(), l.Field()) || !equalFields(m.Peek(), l.Peek()) {
+			t.Errorf("%s: board reads back different", name)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	f := NewField(9, 3)
+	data, _ := f.MarshalBinary()
+	newer := append([]byte(nil), data...)
+	newer[len(fieldMagic)]++
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     []byte("GLL\x01"),
+		"version":   newer,
+		"truncated": data[:len(data)-1],
+		"trailing":  append(append([]byte(nil), data...), 0),
+		"huge":      []byte("GLF\x01\xff\xff\xff\xff\xff\xff\xff\xff"),
+	} {
+		var g Field
+		if err := g.UnmarshalBinary(data); !errors.Is(err, ErrBadPattern) {
+			t.Errorf("%s: got %v, want a bad pattern", name, err)
+		}
+	}
+	for name, data := range map[string]string{
+		"version": `{"version":2,"width":1,"height":1,"rows":["O"]}`,
+		"rows":    `{"version":1,"width":1,"height":2,"rows":["O"]}`,
+		"width":   `{"version":1,"width":2,"height":1,"rows":["O"]}`,
+		"cell":    `{"version":1,"width":1,"height":1,"rows":["x"]}`,
+	} {
+		var g Field
+		if err := json.Unmarshal([]byte(data), &g); !errors.Is(err, ErrBadPattern) {
+			t.Errorf("%s: got %v, want a bad pattern", name, err)
+		}
+	}
+	l := NewLife(nil, nil, 1, 1, 0)
+	err := json.Unmarshal([]byte(`{"version":1,"rule":"B9/S","field":{"version":1,"width":0,"height":0}}`), l)
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("rule: got %v, want an invalid rule", err)
+	}
+}
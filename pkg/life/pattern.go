@@ -0,0 +1,125 @@
+package life
+
+import "image"
+
+// Pattern is a shape of live cells with its metadata, as read from a pattern
+// file. Its cells are kept as a list, so the transforms and the stamps of the
+// mostly empty patterns do not go through all of their dead cells.
+type Pattern struct {
+	// Name is the name given by the file, if any.
+	Name string
+	// Rule is the rule given by the header of an RLE file, if any.
+	Rule string
+	// Width and Height are the size of the shape. The cells are inside it.
+	Width, Height uint
+	// Cells holds the positions of the live cells inside the shape.
+	Cells []image.Point
+	// X and Y are added to the position given to Stamp, moved by
+	// Translate.
+	X, Y int
+}
+
+// NewPattern returns a pattern with the live cells of f and the given name.
+func NewPattern(f *Field, name string) *Pattern {
+	p := &Pattern{Name: name, Width: f.w, Height: f.h}
+	f.ForEachAlive(func(x, y int) {
+		p.Cells = append(p.Cells, image.Pt(x, y))
+	})
+	return p
+}
+
+// ParseRLEPattern reads a pattern in the RLE format like ParseRLE, keeping the
+// rule of its header.
+func ParseRLEPattern(data []byte) (*Pattern, error) {
+	f, name, rule, err := parseRLE(data)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPattern(f, name)
+	p.Rule = rule
+	return p, nil
+}
+
+// ParsePlaintextPattern reads a pattern in the plaintext format like
+// ParsePlaintext.
+func ParsePlaintextPattern(data []byte) (*Pattern, error) {
+	f, name, err := ParsePlaintext(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewPattern(f, name), nil
+}
+
+// ParseLifePattern reads a pattern in the Life 1.05 or 1.06 format like
+// ParseLife, keeping the rule of its "#R" or "#N" line.
+func ParseLifePattern(data []byte) (*Pattern, error) {
+	f, rule, err := ParseLife(data)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPattern(f, "")
+	p.Rule = rule
+	return p, nil
+}
+
+// Field returns a field of the size of the pattern with its live cells,
+// ignoring the translation.
+func (p *Pattern) Field() *Field {
+	f := NewField(p.Width, p.Height)
+	for _, c := range p.Cells {
+		f.Set(uint(c.X), uint(c.Y), true)
+	}
+	return f
+}
+
+// transform returns a copy of the pattern with the given size and every cell
+// moved by fn.
+func (p *Pattern) transform(w, h uint, fn func(c image.Point) image.Point) *Pattern {
+	q := *p
+	q.Width, q.Height = w, h
+	q.Cells = make([]image.Point, len(p.Cells))
+	for i, c := range p.Cells {
+		q.Cells[i] = fn(c)
+	}
+	return &q
+}
+
+// Rotate returns a copy of the pattern rotated 90 degrees clockwise. The width
+// and height of the result are swapped.
+func (p *Pattern) Rotate() *Pattern {
+	return p.transform(p.Height, p.Width, func(c image.Point) image.Point {
+		return image.Pt(int(p.Height)-1-c.Y, c.X)
+	})
+}
+
+// FlipHorizontal returns a copy of the pattern mirrored from left to right.
+func (p *Pattern) FlipHorizontal() *Pattern {
+	return p.transform(p.Width, p.Height, func(c image.Point) image.Point {
+		return image.Pt(int(p.Width)-1-c.X, c.Y)
+	})
+}
+
+// FlipVertical returns a copy of the pattern mirrored from top to bottom.
+func (p *Pattern) FlipVertical() *Pattern {
+	return p.transform(p.Width, p.Height, func(c image.Point) image.Point {
+		return image.Pt(c.X, int(p.Height)-1-c.Y)
+	})
+}
+
+// Translate returns a copy of the pattern stamped dx, dy cells away from the
+// positions given to Stamp.
+func (p *Pattern) Translate(dx, dy int) *Pattern {
+	q := *p
+	q.Cells = append([]image.Point(nil), p.Cells...)
+	q.X, q.Y = p.X+dx, p.Y+dy
+	return &q
+}
+
+// Stamp sets the live cells of the pattern onto the field, with the top-left
+// corner of its shape at x, y plus its translation. Cells falling outside the
+// field wrap around the edges.
+func (p *Pattern) Stamp(onto *Field, x, y int) {
+	for _, c := range p.Cells {
+		onto.SetState(uint(wrap(x+p.X+c.X, int(onto.w))), uint(wrap(y+p.Y+c.Y, int(onto.h))), Live)
+	}
+}
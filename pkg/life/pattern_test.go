@@ -0,0 +1,165 @@
+package life
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestPatternTransforms checks that the transforms of the patterns match the
+// ones of the fields.
+func TestPatternTransforms(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for _, size := range propertySizes {
+		f := randomField(r, size[0], size[1])
+		p := NewPattern(f, "soup")
+		if !equalFields(p.Field(), f) {
+			t.Fatalf("%v: the pattern differs from its field", size)
+		}
+		if !equalFields(p.Rotate().Field(), f.Rotate()) {
+			t.Errorf("%v: rotated differently", size)
+		}
+		g := f.Copy()
+		g.FlipHorizontal()
+		if !equalFields(p.FlipHorizontal().Field(), g) {
+			t.Errorf("%v: flipped horizontally differently", size)
+		}
+		g = f.Copy()
+		g.FlipVertical()
+		if !equalFields(p.FlipVertical().Field(), g) {
+			t.Errorf("%v: flipped vertically differently", size)
+		}
+		if !equalFields(p.Field(), f) {
+			t.Errorf("%v: the transforms changed the pattern", size)
+		}
+	}
+}
+
+func TestPatternStamp(t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	f := randomField(r, 5, 4)
+	p := NewPattern(f, "soup")
+	want := NewField(16, 16)
+	want.Stamp(f, 14, 3)
+	got := NewField(16, 16)
+	p.Stamp(got, 14, 3)
+	if !equalFields(got, want) {
+		t.Error("stamped differently from the field")
+	}
+	got = NewField(16, 16)
+	p.Translate(10, -1).Stamp(got, 4, 4)
+	if !equalFields(got, want) {
+		t.Error("translated pattern stamped at the wrong place")
+	}
+}
+
+func TestParseRLEPattern(t *testing.T) {
+	p, err := ParseRLEPattern([]byte("#N Glider\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Glider" || p.Rule != "B3/S23" || p.Width != 3 || p.Height != 3 || len(p.Cells) != 5 {
+		t.Errorf("got %+v", p)
+	}
+	p, err = ParsePlaintextPattern([]byte("!Name: Blinker\nOOO\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Blinker" || p.Rule != "" || p.Width != 3 || p.Height != 1 || len(p.Cells) != 3 {
+		t.Errorf("got %+v", p)
+	}
+}
+
+// TestMultiStateRLE checks that the dying states of the Generations rules are
+// written in the multi-state RLE format and read back, and that the plaintext
+// format refuses them.
+func TestMultiStateRLE(t *testing.T) {
+	f := stateField([]string{"OO2.", "3...", "...."})
+	f.SetState(3, 2, 25)
+	f.SetState(2, 2, 255)
+	var b strings.Builder
+	if err := WriteRLE(&b, "states", "B2/S/C3", f); err != nil {
+		t.Fatal(err)
+	}
+	const want = "#N states\nx = 4, y = 3, rule = B2/S/C3\n2AB$C$2.yOpA!\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+	g, _, err := ParseRLE([]byte(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equalFields(g, f) {
+		t.Errorf("read back %v, want %v", g.s, f.s)
+	}
+	// The lowercase letters other than b and the prefixes are live cells,
+	// like in the files of two states.
+	if g, _, err = ParseRLE([]byte("x = 3, y = 1\nozp!")); err != nil || g.Population() != 3 {
+		t.Errorf("got %v, population %d", err, g.Population())
+	}
+	if _, _, err := ParseRLE([]byte("x = 1, y = 1\nyX!")); !errors.Is(err, ErrBadPattern) {
+		t.Errorf("state 264: got %v, want a bad pattern", err)
+	}
+	if err := WritePlaintext(&b, "states", f); !errors.Is(err, ErrDyingStates) {
+		t.Errorf("plaintext: got %v, want dying states", err)
+	}
+}
+
+func TestParseLife(t *testing.T) {
+	glider := NewField(3, 3)
+	for _, c := range [][2]uint{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}} {
+		glider.Set(c[0], c[1], true)
+	}
+	for name, tc := range map[string]struct {
+		data, rule string
+	}{
+		"1.05":   {"#Life 1.05\n#D Glider\n#N\n#P -1 -1\n.*\n..*\n***\n", "B3/S23"},
+		"blocks": {"#Life 1.05\n#R 23/3\n#P 5 5\n.*\n#P 6 6\n.*\n#P 5 7\n***\n", "23/3"},
+		"1.06":   {"#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n", ""},
+		"crlf":   {"\r\n#Life 1.06\r\n10 9\r\n11 10\r\n9 11\r\n10 11\r\n11 11\r\n", ""},
+	} {
+		p, err := ParseLifePattern([]byte(tc.data))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !equalFields(p.Field(), glider) || p.Rule != tc.rule {
+			t.Errorf("%s: got %+v", name, p)
+		}
+	}
+	for name, data := range map[string]string{
+		"header":     "#Life 1.04\n0 0\n",
+		"cell":       "#Life 1.05\n.o\n",
+		"position":   "#Life 1.05\n#P 1\n*\n",
+		"coordinate": "#Life 1.06\n0 x\n",
+		"fields":     "#Life 1.06\n0 0 0\n",
+		"huge":       "#Life 1.06\n0 0\n2000000000 2000000000\n",
+	} {
+		if _, _, err := ParseLife([]byte(data)); !errors.Is(err, ErrBadPattern) {
+			t.Errorf("%s: got %v, want a bad pattern", name, err)
+		}
+	}
+}
+
+// TestParseLongLines checks that the lines longer than the buffer of the
+// scanners are read whole, and that the ones longer than the largest
+// patterns are errors instead of cutting the pattern short.
+func TestParseLongLines(t *testing.T) {
+	row := strings.Repeat(".", 1<<17) + "O"
+	f, _, err := ParsePlaintext([]byte(row + "\n" + row + "\n"))
+	if err != nil || f.Width() != 1<<17+1 || f.Population() != 2 {
+		t.Errorf("plaintext: got %v", err)
+	}
+	f, _, err = ParseRLE([]byte("#C " + row + "\nx = 3, y = 3\nbo$2bo$3o!\n"))
+	if err != nil || f.Population() != 5 {
+		t.Errorf("RLE: got %v", err)
+	}
+	long := strings.Repeat(".", maxPatternCells+3)
+	if _, _, err := ParsePlaintext([]byte("O\n" + long)); !errors.Is(err, ErrBadPattern) {
+		t.Errorf("plaintext: got %v, want a bad pattern", err)
+	}
+	if _, _, err := ParseRLE([]byte("x = 3, y = 3\n#C " + long + "\nbo$2bo$3o!\n")); !errors.Is(err, ErrBadPattern) {
+		t.Errorf("RLE: got %v, want a bad pattern", err)
+	}
+}
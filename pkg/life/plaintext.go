@@ -0,0 +1,115 @@
+package life
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxPatternCells is the largest number of cells of the patterns read, so the
+// broken or hostile files cannot take all the memory.
+const maxPatternCells = 1 << 24
+
+// errTooLarge is returned by the parsers of the patterns over maxPatternCells.
+var errTooLarge = fmt.Errorf("%w: too large", ErrBadPattern)
+
+// newScanner returns a scanner of the lines of data, taking the lines as long
+// as the largest patterns.
+func newScanner(data []byte) *bufio.Scanner {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxPatternCells+2)
+	return scanner
+}
+
+// scanError returns the error that stopped the scanner, if any, wrapping
+// ErrBadPattern.
+func scanError(scanner *bufio.Scanner) error {
+	switch err := scanner.Err(); {
+	case err == nil:
+		return nil
+	case errors.Is(err, bufio.ErrTooLong):
+		return errTooLarge
+	default:
+		return fmt.Errorf("%w: %v", ErrBadPattern, err)
+	}
+}
+
+// ParsePlaintext reads a pattern in the plaintext (.cells) format, returning its
+// cells and the name of its "!Name:" line, if any. The errors wrap
+// ErrBadPattern.
+// See: https://conwaylife.com/wiki/Plaintext
+func ParsePlaintext(data []byte) (f *Field, name string, err error) {
+	var rows []string
+	w := 0
+	scanner := newScanner(data)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "!") {
+			if n, ok := cutPrefix(line, "!Name:"); ok {
+				name = strings.TrimSpace(n)
+			}
+			continue
+		}
+		if len(line) > w {
+			w = len(line)
+		}
+		rows = append(rows, line)
+		if w*len(rows) > maxPatternCells {
+			return nil, "", errTooLarge
+		}
+	}
+	if err := scanError(scanner); err != nil {
+		return nil, "", err
+	}
+	f = NewField(uint(w), uint(len(rows)))
+	for y, row := range rows {
+		for x, r := range row {
+			switch r {
+			case '.':
+			case 'O', '*':
+				f.Set(uint(x), uint(y), true)
+			default:
+				return nil, "", fmt.Errorf("%w: invalid cell %q", ErrBadPattern, r)
+			}
+		}
+	}
+	return f, name, nil
+}
+
+// WritePlaintext writes f in the plaintext (.cells) format, with the given name.
+// The format holds the live cells only, so a field with dying cells returns an
+// error wrapping ErrDyingStates without writing anything.
+func WritePlaintext(w io.Writer, name string, f *Field) error {
+	for y, row := range f.s {
+		for x, st := range row {
+			if st > Live {
+				return fmt.Errorf("%w: the plaintext format holds the live cells only, cell %d,%d is in state %d", ErrDyingStates, x, y, st)
+			}
+		}
+	}
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "!Name: %s\n", name)
+	for _, row := range f.s {
+		for _, st := range row {
+			if st == Live {
+				b.WriteByte('O')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.Flush()
+}
+
+// cutPrefix returns s without the provided leading prefix string and reports
+// whether it found the prefix.
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
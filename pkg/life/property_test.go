@@ -0,0 +1,375 @@
+package life
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// propertyRules hold the rules of the property tests, from the usual ones to
+// the ones with births on few neighbors that fill the boards.
+var propertyRules = []string{"B3/S23", "B36/S23", "B3678/S34678", "B2/S", "B1357/S1357", "B35678/S5678", "B0/S8", "B012345678/S012345678"}
+
+// propertySizes hold the sizes of the boards of the property tests, including
+// the ones so small that the cells wrap around onto themselves.
+var propertySizes = [][2]uint{{1, 1}, {2, 3}, {3, 3}, {7, 1}, {16, 16}, {33, 17}, {64, 40}}
+
+// referenceStep returns the next generation of the field on the torus with the
+// given rule, counting the neighbors from the live cells only, like a sparse
+// engine would. On the boards smaller than the neighborhood a cell is counted
+// once for every neighbor position it wraps to, like Life does. It follows the
+// definition of the rule with tables of its own: a dead cell is born if its
+// count is in B, a live cell survives if its count is in S, and the other cells
+// are dead.
+func referenceStep(f *Field, birth, survival []uint) *Field {
+	var born, survives [9]bool
+	for _, n := range birth {
+		born[n] = true
+	}
+	for _, n := range survival {
+		survives[n] = true
+	}
+	counts := make([][]uint, f.h)
+	for y := range counts {
+		counts[y] = make([]uint, f.w)
+	}
+	for y := 0; y < int(f.h); y++ {
+		for x := 0; x < int(f.w); x++ {
+			if f.s[y][x] != Live {
+				continue
+			}
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if dx != 0 || dy != 0 {
+						counts[wrap(y+dy, int(f.h))][wrap(x+dx, int(f.w))]++
+					}
+				}
+			}
+		}
+	}
+	next := NewField(f.w, f.h)
+	for y, row := range counts {
+		for x, n := range row {
+			if f.s[y][x] == Live {
+				next.Set(uint(x), uint(y), survives[n])
+			} else {
+				next.Set(uint(x), uint(y), born[n])
+			}
+		}
+	}
+	return next
+}
+
+// TestReferenceStep checks the reference against evolutions worked out by
+// hand, on rules where B and S differ.
+func TestReferenceStep(t *testing.T) {
+	for _, tc := range []struct {
+		rule       string
+		from, want string
+	}{
+		// The blinker turns.
+		{"B3/S23", ".....\n.....\n.OOO.\n.....\n.....", ".....\n..O..\n..O..\n..O..\n....."},
+		// Every live cell of Seeds dies, and the cells with two
+		// neighbors are born.
+		{"B2/S", ".....\n.....\n.OOO.\n.....\n.....", ".....\n.O.O.\n.....\n.O.O.\n....."},
+		// The centre of the 3x3 block has 8 neighbors and dies; the
+		// corners have 3 and survive, the edges have 5 and die, and the
+		// cells next to the edges have 3 and are born.
+		{"B36/S23", ".......\n.......\n..OOO..\n..OOO..\n..OOO..\n.......\n.......", ".......\n...O...\n..O.O..\n.O...O.\n..O.O..\n...O...\n......."},
+		// The live cell at 2,2 has 6 neighbors, which are in B but not
+		// in S, so it dies.
+		{"B36/S23", ".....\n.OOO.\n.OOO.\n.O...\n.....", "..O..\n.O.O.\nO..O.\n.O...\n....."},
+	} {
+		birth, survival, err := ParseRule(tc.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, _, err := ParsePlaintext([]byte(tc.from))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, err := ParsePlaintext([]byte(tc.want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := referenceStep(from, birth, survival); !equalFields(got, want) {
+			t.Errorf("%s: the reference differs from the evolution by hand", tc.rule)
+		}
+	}
+}
+
+// randomField returns a field of the given size with a random soup.
+func randomField(r *rand.Rand, w, h uint) *Field {
+	f := NewField(w, h)
+	density := r.Float64()
+	for y := range f.s {
+		for x := range f.s[y] {
+			f.Set(uint(x), uint(y), r.Float64() < density)
+		}
+	}
+	return f
+}
+
+// equalFields reports whether two fields have the same size and cells.
+func equalFields(a, b *Field) bool {
+	if a.w != b.w || a.h != b.h {
+		return false
+	}
+	for y := range a.s {
+		for x := range a.s[y] {
+			if a.s[y][x] != b.s[y][x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestStepMatchesReference(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, rule := range propertyRules {
+		birth, survival, err := ParseRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, size := range propertySizes {
+			for soup := 0; soup < 10; soup++ {
+				f := randomField(r, size[0], size[1])
+				l := NewLife(birth, survival, size[0], size[1], 0)
+				l.SetField(f.Copy())
+				for gen := 1; gen <= 20; gen++ {
+					want := referenceStep(l.Field(), birth, survival)
+					if peek := l.Peek(); !equalFields(peek, want) {
+						t.Fatalf("%s %dx%d soup %d: Peek differs from the reference at generation %d", rule, size[0], size[1], soup, gen)
+					}
+					l.Step()
+					if !equalFields(l.Field(), want) {
+						t.Fatalf("%s %dx%d soup %d: Step differs from the reference at generation %d", rule, size[0], size[1], soup, gen)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestStepCommutesWithSymmetries checks that stepping a rotated, mirrored or
+// shifted board gives the rotated, mirrored or shifted step, as the rules are
+// isotropic and the torus has no edges.
+func TestStepCommutesWithSymmetries(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	transforms := map[string]func(f *Field) *Field{
+		"rotate": func(f *Field) *Field { return f.Rotate() },
+		"mirror": func(f *Field) *Field { f = f.Copy(); f.FlipHorizontal(); return f },
+		"flip":   func(f *Field) *Field { f = f.Copy(); f.FlipVertical(); return f },
+		"shift":  func(f *Field) *Field { f = f.Copy(); f.Shift(5, -3); return f },
+	}
+	for _, rule := range propertyRules {
+		birth, survival, _ := ParseRule(rule)
+		for _, size := range propertySizes {
+			f := randomField(r, size[0], size[1])
+			for name, transform := range transforms {
+				got := referenceStep(transform(f), birth, survival)
+				want := transform(referenceStep(f, birth, survival))
+				l := NewLife(birth, survival, size[0], size[1], 0)
+				l.SetField(f.Copy())
+				l.Step()
+				if !equalFields(got, want) || !equalFields(transform(l.Field()), want) {
+					t.Errorf("%s %dx%d: step and %s do not commute", rule, size[0], size[1], name)
+				}
+			}
+		}
+	}
+}
+
+// knownPeriods hold patterns of the library of the game with their periods on B3/S23 and
+// how far they move in a period.
+var knownPeriods = []struct {
+	name   string
+	period int
+	dx, dy int
+}{
+	{name: "Block", period: 1},
+	{name: "Beehive", period: 1},
+	{name: "Blinker", period: 2},
+	{name: "Toad", period: 2},
+	{name: "Beacon", period: 2},
+	{name: "Pulsar", period: 3},
+	{name: "Pentadecathlon", period: 15},
+	{name: "Glider", period: 4, dx: 1, dy: 1},
+	{name: "LWSS", period: 4, dx: -2},
+}
+
+func TestKnownPeriods(t *testing.T) {
+	birth, survival, _ := ParseRule("B3/S23")
+	for _, k := range knownPeriods {
+		data, err := os.ReadFile(filepath.Join("..", "..", "patterns", strings.ToLower(k.name)+".cells"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _, err := ParsePlaintext(data)
+		if err != nil {
+			t.Fatalf("%s: %v", k.name, err)
+		}
+		l := NewLife(birth, survival, 32, 32, 0)
+		l.Field().Stamp(p, 8, 8)
+		start := l.Field().Copy()
+		for gen := 1; gen <= k.period; gen++ {
+			l.Step()
+			moved := start.Copy()
+			moved.Shift(k.dx, k.dy)
+			if equal := equalFields(l.Field(), moved); equal != (gen == k.period) {
+				t.Errorf("%s: generation %d repeats the start: %v, want period %d", k.name, gen, equal, k.period)
+			}
+		}
+	}
+}
+
+// TestOnStep checks that the functions added with OnStep are called after
+// every step, in order, with the changes of the step.
+func TestOnStep(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	birth, survival, _ := ParseRule("B3/S23")
+	l := NewLife(birth, survival, 33, 17, 0)
+	l.SetField(randomField(r, 33, 17))
+	var calls []int
+	var got StepStats
+	l.OnStep(func(gen uint64, stats StepStats) {
+		if gen != l.Generation() {
+			t.Errorf("called with generation %d, want %d", gen, l.Generation())
+		}
+		calls = append(calls, 1)
+		got = stats
+	})
+	l.OnStep(func(uint64, StepStats) { calls = append(calls, 2) })
+	for gen := uint64(1); gen <= 20; gen++ {
+		calls = calls[:0]
+		l.Step()
+		if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 {
+			t.Fatalf("generation %d: calls %v, want [1 2]", gen, calls)
+		}
+		births, deaths := l.Turnover()
+		want := StepStats{Births: births, Deaths: deaths, Population: l.Field().Population()}
+		if got != want {
+			t.Fatalf("generation %d: stats %+v, want %+v", gen, got, want)
+		}
+	}
+	if l.Generation() != 20 {
+		t.Errorf("generation %d after 20 steps", l.Generation())
+	}
+}
+
+// TestForEachAlive checks that ForEachAlive visits every live cell once, row by
+// row, and no dead one.
+func TestForEachAlive(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for _, size := range propertySizes {
+		f := randomField(r, size[0], size[1])
+		var want [][2]int
+		for y := 0; y < int(f.Height()); y++ {
+			for x := 0; x < int(f.Width()); x++ {
+				if f.Alive(x, y) {
+					want = append(want, [2]int{x, y})
+				}
+			}
+		}
+		var got [][2]int
+		f.ForEachAlive(func(x, y int) { got = append(got, [2]int{x, y}) })
+		if len(got) != len(want) {
+			t.Fatalf("%dx%d: %d cells, want %d", size[0], size[1], len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%dx%d: cell %d at %v, want %v", size[0], size[1], i, got[i], want[i])
+			}
+		}
+	}
+}
+
+// TestDiff checks that applying the changes returned by Diff to a field gives
+// the other one, also when their sizes differ.
+func TestDiff(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for _, a := range propertySizes {
+		for _, b := range propertySizes {
+			f, g := randomField(r, a[0], a[1]), randomField(r, b[0], b[1])
+			w, h := a[0], a[1]
+			if b[0] > w {
+				w = b[0]
+			}
+			if b[1] > h {
+				h = b[1]
+			}
+			got := f.Resize(w, h, TopLeft)
+			for _, c := range f.Diff(g) {
+				if got.Alive(c.X, c.Y) == c.Alive {
+					t.Fatalf("%v to %v: %+v does not change the cell", a, b, c)
+				}
+				got.Set(uint(c.X), uint(c.Y), c.Alive)
+			}
+			if want := g.Resize(w, h, TopLeft); !equalFields(got, want) {
+				t.Errorf("%v to %v: the changes give a different field", a, b)
+			}
+		}
+	}
+	if changes := randomField(r, 16, 16).Diff(NewField(16, 16)); len(changes) == 0 {
+		t.Error("no changes from a random field to an empty one")
+	}
+}
+
+// TestPopulationAndBoundingBox checks that the population and the bounding box
+// kept by the changes of the cells and the steps match the ones found scanning
+// the field.
+func TestPopulationAndBoundingBox(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	check := func(what string, f *Field) {
+		t.Helper()
+		n := uint(0)
+		for _, row := range f.s {
+			for _, st := range row {
+				if st == Live {
+					n++
+				}
+			}
+		}
+		box, ok := f.BoundingBox()
+		want, wantOK := f.scanBox()
+		if f.Population() != n || box != want || ok != wantOK {
+			t.Fatalf("%s: population %d and box %+v %v, want %d and %+v %v", what, f.Population(), box, ok, n, want, wantOK)
+		}
+	}
+	for _, size := range propertySizes {
+		l := NewLife([]uint{3}, []uint{2, 3}, size[0], size[1], 0)
+		l.SetField(randomField(r, size[0], size[1]))
+		check("random", l.Field())
+		for i := 0; i < 200; i++ {
+			f := l.Field()
+			x, y := uint(r.Intn(int(size[0]))), uint(r.Intn(int(size[1])))
+			switch r.Intn(8) {
+			case 0:
+				l.Step()
+			case 1:
+				f.Shift(r.Intn(5)-2, r.Intn(5)-2)
+			case 2:
+				f.FlipHorizontal()
+			case 3:
+				f.FlipVertical()
+			case 4:
+				f.Stamp(randomField(r, 3, 3), int(x), int(y))
+			default:
+				f.Set(x, y, r.Intn(3) == 0)
+			}
+			check(fmt.Sprintf("%v step %d", size, i), l.Field())
+			if l.Population() != uint64(l.Field().Population()) {
+				t.Fatalf("%v step %d: the game and its field disagree", size, i)
+			}
+		}
+		check("rotate", l.Field().Rotate())
+		check("resize", l.Field().Resize(size[0]+3, size[1]+1, Center))
+		check("crop", l.Field().Crop(Rect{W: size[0] / 2, H: size[1]}))
+		l.Field().Clear()
+		check("clear", l.Field())
+	}
+}
@@ -0,0 +1,216 @@
+package life
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseRLE reads a pattern in the run length encoded (.rle) format, returning
+// its cells and the name of its "#N" line, if any. The cells of the multi-state
+// files written by WriteRLE keep their states. The errors wrap ErrBadPattern.
+// See: https://conwaylife.com/wiki/Run_Length_Encoded
+func ParseRLE(data []byte) (f *Field, name string, err error) {
+	f, name, _, err = parseRLE(data)
+	return f, name, err
+}
+
+// parseRLE reads a pattern in the RLE format like ParseRLE, also returning the
+// rule of its header, if any.
+func parseRLE(data []byte) (f *Field, name, rule string, err error) {
+	var w, h int
+	var text strings.Builder
+	header := false
+	scanner := newScanner(data)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+			if n, ok := cutPrefix(line, "#N"); ok {
+				name = strings.TrimSpace(n)
+			}
+		case !header && strings.HasPrefix(line, "x"):
+			header = true
+			for _, item := range strings.Split(line, ",") {
+				key, value, _ := strings.Cut(item, "=")
+				key = strings.TrimSpace(key)
+				if key == "rule" {
+					rule = strings.TrimSpace(value)
+				}
+				if key != "x" && key != "y" {
+					continue
+				}
+				n, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil || n < 0 || n > maxPatternCells {
+					return nil, "", "", fmt.Errorf("%w: invalid size in the header: %s", ErrBadPattern, strings.TrimSpace(item))
+				}
+				if key == "x" {
+					w = n
+				} else {
+					h = n
+				}
+			}
+		default:
+			text.WriteString(line)
+		}
+	}
+	if err := scanError(scanner); err != nil {
+		return nil, "", "", err
+	}
+	if !header {
+		return nil, "", "", fmt.Errorf("%w: missing header", ErrBadPattern)
+	}
+
+	type cell struct {
+		x, y int
+		st   State
+	}
+	var cells []cell
+	x, y, count := 0, 0, 0
+	body := []rune(text.String())
+loop:
+	for i := 0; i < len(body); i++ {
+		r := body[i]
+		n := count
+		if n == 0 {
+			n = 1
+		}
+		st := Live
+		switch {
+		case r >= '0' && r <= '9':
+			count = count*10 + int(r-'0')
+			if count > maxPatternCells {
+				return nil, "", "", errTooLarge
+			}
+			continue
+		case r == 'b' || r == '.':
+			x += n
+		case r == '$':
+			x, y = 0, y+n
+		case r == '!':
+			break loop
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			// The multi-state files name the states from A, and the
+			// ones past X with a prefix from p.
+			switch {
+			case r >= 'A' && r <= 'X':
+				st = State(r - 'A' + 1)
+			case r >= 'p' && r <= 'y' && i+1 < len(body) && body[i+1] >= 'A' && body[i+1] <= 'X':
+				i++
+				v := 24*int(r-'p'+1) + int(body[i]-'A'+1)
+				if v > 255 {
+					return nil, "", "", fmt.Errorf("%w: invalid state %c%c", ErrBadPattern, r, body[i])
+				}
+				st = State(v)
+			}
+			// The cells so far fit in the board up to this row.
+			right := x + n
+			if right < w {
+				right = w
+			}
+			if right*(y+1) > maxPatternCells {
+				return nil, "", "", errTooLarge
+			}
+			for j := 0; j < n; j++ {
+				cells = append(cells, cell{x + j, y, st})
+			}
+			x += n
+		default:
+			return nil, "", "", fmt.Errorf("%w: invalid cell %q", ErrBadPattern, r)
+		}
+		count = 0
+		if x > maxPatternCells || y > maxPatternCells {
+			return nil, "", "", errTooLarge
+		}
+		if x > w {
+			w = x
+		}
+		if y >= h {
+			h = y + 1
+		}
+	}
+	if w*h > maxPatternCells {
+		return nil, "", "", errTooLarge
+	}
+	f = NewField(uint(w), uint(h))
+	for _, c := range cells {
+		f.SetState(uint(c.x), uint(c.y), c.st)
+	}
+	return f, name, rule, nil
+}
+
+// WriteRLE writes f in the run length encoded (.rle) format, with the given
+// name and rule in its header. The fields with dying cells are written in the
+// multi-state format of Golly, with . for the dead cells, A for the live ones
+// and B onwards for the dying states.
+func WriteRLE(w io.Writer, name, rule string, f *Field) error {
+	multi := false
+	for _, row := range f.s {
+		for _, st := range row {
+			multi = multi || st > Live
+		}
+	}
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "#N %s\n", name)
+	fmt.Fprintf(b, "x = %d, y = %d, rule = %s\n", f.w, f.h, rule)
+	// Lines are at most 70 characters long.
+	line := 0
+	emit := func(n int, tag string) {
+		s := tag
+		if n > 1 {
+			s = strconv.Itoa(n) + s
+		}
+		if line+len(s) > 70 {
+			b.WriteByte('\n')
+			line = 0
+		}
+		b.WriteString(s)
+		line += len(s)
+	}
+	rows := 0
+	for _, row := range f.s {
+		// Dead cells at the end of a row are left out.
+		end := len(row)
+		for end > 0 && row[end-1] == Dead {
+			end--
+		}
+		if end == 0 {
+			rows++
+			continue
+		}
+		if rows > 0 {
+			emit(rows, "$")
+		}
+		for x := 0; x < end; {
+			n := 1
+			for x+n < end && row[x+n] == row[x] {
+				n++
+			}
+			emit(n, rleTag(row[x], multi))
+			x += n
+		}
+		rows = 1
+	}
+	emit(1, "!")
+	b.WriteByte('\n')
+	return b.Flush()
+}
+
+// rleTag returns the tag of the cells of the given state, in the multi-state
+// format if multi.
+func rleTag(st State, multi bool) string {
+	switch {
+	case st == Dead && multi:
+		return "."
+	case st == Dead:
+		return "b"
+	case !multi:
+		return "o"
+	case st <= 24:
+		return string(rune('A' + st - 1))
+	}
+	v := int(st) - 1
+	return string([]rune{rune('p' + v/24 - 1), rune('A' + v%24)})
+}
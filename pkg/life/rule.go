@@ -0,0 +1,228 @@
+package life
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+func parseDigits(name, s string) ([]uint, error) {
+	var result []uint
+	for _, r := range s {
+		if !unicode.IsDigit(r) || (r < '0' || r > '8') {
+			return nil, fmt.Errorf("%w, use only [0-8] digits for the %s: %s", ErrInvalidRule, name, s)
+		}
+		result = append(result, uint(r-'0'))
+	}
+	slices.Sort(result)
+
+	return slices.Compact(result), nil
+}
+
+// parseStates reads the number of states of a rule of the Generations family,
+// or returns 2 if it is not given.
+func parseStates(s string) (uint8, error) {
+	if s == "" {
+		return 2, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || n < 2 {
+		return 0, fmt.Errorf("%w, use from 2 to 255 states: %s", ErrInvalidRule, s)
+	}
+	return uint8(n), nil
+}
+
+func parseBS(s string) (birth, survival []uint, states uint8, err error) {
+	re := regexp.MustCompile(`(?i)^B(\d+)/S(\d*)(?:/C(\d+))?$`)
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, nil, 0, fmt.Errorf("%w in the B/S notation: %s", ErrInvalidRule, s)
+	}
+	if birth, err = parseDigits("birth", m[1]); err != nil {
+		return nil, nil, 0, err
+	}
+	if survival, err = parseDigits("survival", m[2]); err != nil {
+		return nil, nil, 0, err
+	}
+	if states, err = parseStates(m[3]); err != nil {
+		return nil, nil, 0, err
+	}
+	return birth, survival, states, nil
+}
+
+func parseSB(s string) (survival, birth []uint, states uint8, err error) {
+	re := regexp.MustCompile(`^(\d*)/(\d+)(?:/(\d+))?$`)
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, nil, 0, fmt.Errorf("%w in the S/B notation: %s", ErrInvalidRule, s)
+	}
+	if survival, err = parseDigits("survival", m[1]); err != nil {
+		return nil, nil, 0, err
+	}
+	if birth, err = parseDigits("birth", m[2]); err != nil {
+		return nil, nil, 0, err
+	}
+	if states, err = parseStates(m[3]); err != nil {
+		return nil, nil, 0, err
+	}
+	return survival, birth, states, nil
+}
+
+// ParseRule reads a rule in the B/S notation of Golly, like B3/S23, if it has a
+// B, or in the S/B notation of MCell, like 23/3, otherwise. It returns the
+// sorted neighbor counts giving births and survivals. The errors wrap
+// ErrInvalidRule.
+func ParseRule(s string) (birth, survival []uint, err error) {
+	birth, survival, _, err = ParseRuleStates(s)
+	return birth, survival, err
+}
+
+// ParseRuleStates is like ParseRule, also reading the number of states of the
+// rules of the Generations family, given after the rule like in B2/S/C3 or
+// 345/2/4. It returns 2 states for the other rules.
+func ParseRuleStates(s string) (birth, survival []uint, states uint8, err error) {
+	if strings.ContainsAny(s, "Bb") {
+		return parseBS(s)
+	}
+	survival, birth, states, err = parseSB(s)
+	return birth, survival, states, err
+}
+
+// Rule is a rule of the Life-like cellular automata: the neighbor counts giving
+// births and survivals, and the number of states of the Generations family,
+// where 0 and 2 both mean the two states of Conway's Game of Life.
+type Rule struct {
+	Birth, Survival []uint
+	States          uint8
+}
+
+// String returns the rule in the canonical B/S notation, with the neighbor
+// counts sorted and without repeats, followed by /C and the number of states
+// for the rules with dying states.
+func (r Rule) String() string {
+	var b strings.Builder
+	b.WriteString("B")
+	writeCounts(&b, r.Birth)
+	b.WriteString("/S")
+	writeCounts(&b, r.Survival)
+	if r.States > 2 {
+		fmt.Fprintf(&b, "/C%d", r.States)
+	}
+	return b.String()
+}
+
+// writeCounts writes the sorted neighbor counts without repeats.
+func writeCounts(b *strings.Builder, counts []uint) {
+	sorted := append([]uint(nil), counts...)
+	slices.Sort(sorted)
+	for i, n := range sorted {
+		if i == 0 || n != sorted[i-1] {
+			fmt.Fprint(b, n)
+		}
+	}
+}
+
+// Equal reports whether the rules give the same births, survivals and states,
+// whatever the order of their neighbor counts.
+func (r Rule) Equal(other Rule) bool {
+	return r.String() == other.String()
+}
+
+// Name returns the name the rule is registered with, or nothing if it has
+// none.
+func (r Rule) Name() string {
+	s := r.String()
+	for _, n := range ruleNames {
+		if n.rule.String() == s {
+			return n.name
+		}
+	}
+	return ""
+}
+
+// namedRule is a rule of the registry with its name.
+type namedRule struct {
+	name string
+	rule Rule
+}
+
+// ruleNames holds the registered rules, in the order they were registered.
+var ruleNames []namedRule
+
+func init() {
+	for _, n := range []struct{ name, rule string }{
+		{"Life", "B3/S23"},
+		{"HighLife", "B36/S23"},
+		{"Seeds", "B2/S"},
+		{"Day & Night", "B3678/S34678"},
+		{"Life without Death", "B3/S012345678"},
+		{"Replicator", "B1357/S1357"},
+		{"2x2", "B36/S125"},
+		{"Maze", "B3/S12345"},
+		{"Brian's Brain", "B2/S/C3"},
+		{"Star Wars", "B2/S345/C4"},
+	} {
+		birth, survival, states, err := ParseRuleStates(n.rule)
+		if err != nil {
+			panic(err)
+		}
+		RegisterRule(n.name, Rule{Birth: birth, Survival: survival, States: states})
+	}
+}
+
+// RegisterRule adds a name for the rule, replacing the rule of the name if it
+// was already registered. The names are matched ignoring case, spaces and
+// punctuation. It is not safe to call while other goroutines look up rules, so
+// it belongs in the init functions.
+func RegisterRule(name string, r Rule) {
+	key := ruleKey(name)
+	for i, n := range ruleNames {
+		if ruleKey(n.name) == key {
+			ruleNames[i] = namedRule{name, r}
+			return
+		}
+	}
+	ruleNames = append(ruleNames, namedRule{name, r})
+}
+
+// RuleNames returns the names of the registered rules, in the order they were
+// registered.
+func RuleNames() []string {
+	names := make([]string, len(ruleNames))
+	for i, n := range ruleNames {
+		names[i] = n.name
+	}
+	return names
+}
+
+// ruleKey returns the name with its letters and digits only, in lower case, so
+// "Day & Night" matches "daynight" and "day_and_night" does not.
+func ruleKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// ParseNamedRule reads a rule by its registered name, like HighLife or
+// day_night, or otherwise in either notation like ParseRuleStates. The errors
+// wrap ErrInvalidRule.
+func ParseNamedRule(s string) (Rule, error) {
+	key := ruleKey(s)
+	for _, n := range ruleNames {
+		if ruleKey(n.name) == key {
+			return n.rule, nil
+		}
+	}
+	birth, survival, states, err := ParseRuleStates(s)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Birth: birth, Survival: survival, States: states}, nil
+}
@@ -0,0 +1,158 @@
+package life
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRuleString(t *testing.T) {
+	for _, tc := range []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Birth: []uint{3}, Survival: []uint{3, 2}}, "B3/S23"},
+		{Rule{Birth: []uint{6, 3, 3}, Survival: []uint{2, 3}, States: 2}, "B36/S23"},
+		{Rule{Birth: []uint{2}}, "B2/S"},
+		{Rule{Birth: []uint{2}, States: 3}, "B2/S/C3"},
+	} {
+		if got := tc.rule.String(); got != tc.want {
+			t.Errorf("%v: got %s, want %s", tc.rule, got, tc.want)
+		}
+	}
+	a := Rule{Birth: []uint{6, 3}, Survival: []uint{3, 2}}
+	b := Rule{Birth: []uint{3, 6}, Survival: []uint{2, 3}, States: 2}
+	if !a.Equal(b) {
+		t.Errorf("%s and %s differ", a, b)
+	}
+	if a.Equal(Rule{Birth: []uint{3, 6}, Survival: []uint{2, 3}, States: 3}) {
+		t.Error("the number of states is ignored")
+	}
+}
+
+func TestParseNamedRule(t *testing.T) {
+	for s, want := range map[string]string{
+		"HighLife":     "B36/S23",
+		"highlife":     "B36/S23",
+		"Seeds":        "B2/S",
+		"Day & Night":  "B3678/S34678",
+		"day_night":    "B3678/S34678",
+		"brians-brain": "B2/S/C3",
+		"36/3":         "B3/S36",
+		"B3/S23":       "B3/S23",
+	} {
+		r, err := ParseNamedRule(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if r.String() != want {
+			t.Errorf("%s: got %s, want %s", s, r, want)
+		}
+	}
+	if _, err := ParseNamedRule("Day and Night"); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("got %v, want an invalid rule", err)
+	}
+	r, _ := ParseNamedRule("b36/s23")
+	if r.Name() != "HighLife" {
+		t.Errorf("name %q, want HighLife", r.Name())
+	}
+	if name := (Rule{Birth: []uint{1}}).Name(); name != "" {
+		t.Errorf("name %q for an unregistered rule", name)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	defer func(names []namedRule) { ruleNames = names }(append([]namedRule(nil), ruleNames...))
+	RegisterRule("Morley", Rule{Birth: []uint{3, 6, 8}, Survival: []uint{2, 4, 5}})
+	r, err := ParseNamedRule("morley")
+	if err != nil || r.String() != "B368/S245" {
+		t.Errorf("got %s, %v", r, err)
+	}
+	n := len(RuleNames())
+	RegisterRule("MORLEY", Rule{Birth: []uint{3}})
+	if len(RuleNames()) != n {
+		t.Error("registering a name again added it")
+	}
+}
+
+// stateField returns a field with a row for every string, with an O for every
+// live cell, a dot for every dead one and the state for every dying one.
+func stateField(rows []string) *Field {
+	f := NewField(uint(len(rows[0])), uint(len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			switch {
+			case c == 'O':
+				f.SetState(uint(x), uint(y), Live)
+			case c >= '2' && c <= '9':
+				f.SetState(uint(x), uint(y), State(c-'0'))
+			}
+		}
+	}
+	return f
+}
+
+// TestNamedRuleEvolutions checks every registered rule on a small evolution
+// worked out by hand, so the names are not given to rules the engine runs
+// differently.
+func TestNamedRuleEvolutions(t *testing.T) {
+	blinker := []string{".......", ".......", ".......", "..OOO..", ".......", ".......", "......."}
+	domino := []string{"........", "........", "........", "...OO...", "........", "........", "........"}
+	cases := map[string][][]string{
+		"Life": {blinker,
+			{".......", ".......", "...O...", "...O...", "...O...", ".......", "......."}},
+		// The dead cell at 3,3 has 6 neighbors and is born, unlike in
+		// Life, and the live cell at 3,2 has 4 and dies.
+		"HighLife": {{".......", ".......", "..OOO..", "..O.O..", "..O....", ".......", "......."},
+			{".......", "...O...", "..O.O..", ".OOOO..", "...O...", ".......", "......."}},
+		"Seeds": {blinker,
+			{".......", ".......", "..O.O..", ".......", "..O.O..", ".......", "......."}},
+		// The middle of the blinker has 2 neighbors and dies.
+		"Day & Night": {blinker,
+			{".......", ".......", "...O...", ".......", "...O...", ".......", "......."}},
+		"Life without Death": {blinker,
+			{".......", ".......", "...O...", "..OOO..", "...O...", ".......", "......."}},
+		// A single cell dies and gives birth to the ring around it.
+		"Replicator": {{".......", ".......", ".......", "...O...", ".......", ".......", "......."},
+			{".......", ".......", "..OOO..", "..O.O..", "..OOO..", ".......", "......."}},
+		// The cells of the block have 3 neighbors and die.
+		"2x2": {{".......", ".......", "..OO...", "..OO...", ".......", ".......", "......."},
+			{".......", ".......", ".......", ".......", ".......", ".......", "......."}},
+		"Maze": {blinker,
+			{".......", ".......", "...O...", "..OOO..", "...O...", ".......", "......."}},
+		// The firing cells start dying and are dead the step after,
+		// while the cells with two firing neighbors fire.
+		"Brian's Brain": {domino,
+			{"........", "........", "...OO...", "...22...", "...OO...", "........", "........"},
+			{"........", "...OO...", "...22...", "..O..O..", "...22...", "...OO...", "........"}},
+		// The same, with a second dying state.
+		"Star Wars": {domino,
+			{"........", "........", "...OO...", "...22...", "...OO...", "........", "........"},
+			{"........", "...OO...", "...22...", "..O33O..", "...22...", "...OO...", "........"}},
+	}
+	for _, name := range RuleNames() {
+		boards, ok := cases[name]
+		if !ok {
+			t.Errorf("%s: no evolution to check", name)
+			continue
+		}
+		r, err := ParseNamedRule(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := NewLife(nil, nil, 1, 1, 0)
+		l.SetRule(r)
+		l.SetField(stateField(boards[0]))
+		for gen, rows := range boards[1:] {
+			l.Step()
+			want := stateField(rows)
+			for y := 0; y < int(want.h); y++ {
+				for x := 0; x < int(want.w); x++ {
+					if got := l.Field().State(x, y); got != want.State(x, y) {
+						t.Errorf("%s: cell %d,%d in state %d at generation %d, want %d", name, x, y, got, gen+1, want.State(x, y))
+					}
+				}
+			}
+		}
+	}
+}
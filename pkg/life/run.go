@@ -0,0 +1,77 @@
+package life
+
+import (
+	"context"
+	"time"
+)
+
+// RunOptions tell Run how fast to step the board and when to stop.
+type RunOptions struct {
+	// Interval is the time between the steps, or 0 to step as fast as
+	// possible.
+	Interval time.Duration
+	// Tick takes the place of Interval when set, stepping on every value
+	// received, so the caller can change the pace while it runs.
+	Tick <-chan time.Time
+	// Generations stops the run after that many generations, or never if 0.
+	Generations uint64
+	// Until stops the run when it returns true. It is checked after every
+	// generation.
+	Until func(l *Life) bool
+	// Step takes the place of the steps of the board when set, to do more
+	// around them or to skip some, like while paused.
+	Step func()
+	// Do receives functions called between the steps, to change the board
+	// while it runs without racing with them.
+	Do <-chan func()
+}
+
+// Run steps the board until the context is done, returning its error, or until
+// the Generations or the Until condition of the options stop it, returning
+// nil.
+func (l *Life) Run(ctx context.Context, opts RunOptions) error {
+	step := opts.Step
+	if step == nil {
+		step = l.Step
+	}
+	tick := opts.Tick
+	if tick == nil && opts.Interval > 0 {
+		t := time.NewTicker(opts.Interval)
+		defer t.Stop()
+		tick = t.C
+	}
+	start, last := l.gen, l.gen
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.Generations > 0 && l.gen-start >= opts.Generations {
+			return nil
+		}
+		if tick == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case fn := <-opts.Do:
+				fn()
+			default:
+				step()
+			}
+		} else {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case fn := <-opts.Do:
+				fn()
+			case <-tick:
+				step()
+			}
+		}
+		if l.gen != last {
+			last = l.gen
+			if opts.Until != nil && opts.Until(l) {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package life
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunGenerations(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 16, 16, 0.5)
+	l.Step()
+	if err := l.Run(context.Background(), RunOptions{Generations: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if l.Generation() != 11 {
+		t.Errorf("generation %d, want 11", l.Generation())
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 16, 16, 0.5)
+	err := l.Run(context.Background(), RunOptions{Until: func(l *Life) bool { return l.Generation() == 7 }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Generation() != 7 {
+		t.Errorf("generation %d, want 7", l.Generation())
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 16, 16, 0.5)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.Run(ctx, RunOptions{Interval: time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if l.Generation() == 0 {
+		t.Error("no steps before the deadline")
+	}
+}
+
+// TestRunDo checks that the functions of Do run between the steps, and that
+// the steps of Step count as generations.
+func TestRunDo(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 16, 16, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	do := make(chan func())
+	tick := make(chan time.Time)
+	go func() {
+		do <- func() { l.Field().Set(1, 1, true) }
+		tick <- time.Time{}
+		do <- cancel
+	}()
+	steps := 0
+	err := l.Run(ctx, RunOptions{Tick: tick, Do: do, Step: func() {
+		steps++
+		l.Step()
+	}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if steps != 1 || l.Generation() != 1 {
+		t.Errorf("%d steps to generation %d, want 1", steps, l.Generation())
+	}
+	// The lonely cell set by Do died on the step.
+	if l.Field().Population() != 0 {
+		t.Errorf("population %d, want 0", l.Field().Population())
+	}
+}
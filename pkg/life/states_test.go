@@ -0,0 +1,124 @@
+package life
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRuleStates(t *testing.T) {
+	for _, tc := range []struct {
+		rule            string
+		birth, survival []uint
+		states          uint8
+	}{
+		{"B3/S23", []uint{3}, []uint{2, 3}, 2},
+		{"B2/S/C3", []uint{2}, nil, 3},
+		{"b2/s/c3", []uint{2}, nil, 3},
+		{"345/2/4", []uint{2}, []uint{3, 4, 5}, 4},
+		{"23/3", []uint{3}, []uint{2, 3}, 2},
+	} {
+		birth, survival, states, err := ParseRuleStates(tc.rule)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.rule, err)
+		}
+		if !reflect.DeepEqual(birth, tc.birth) || !reflect.DeepEqual(survival, tc.survival) || states != tc.states {
+			t.Errorf("%s: got %v/%v/%d, want %v/%v/%d", tc.rule, birth, survival, states, tc.birth, tc.survival, tc.states)
+		}
+	}
+	for _, rule := range []string{"B2/S/C1", "B2/S/C256", "345/2/0"} {
+		if _, _, _, err := ParseRuleStates(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("%s: got %v, want an invalid rule", rule, err)
+		}
+	}
+}
+
+func TestParseRuleRejects(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"B3/S239",
+		"B9/S23",
+		"xB3/S23y",
+		"B3/S23 ",
+		"B3/S23/C3x",
+		"B/S23",
+		"B3S23",
+		"23/39",
+		"9/3",
+		"23/3x",
+		"23/",
+		"/3/",
+		"23/3/4/5",
+	} {
+		if _, _, _, err := ParseRuleStates(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("%q: got %v, want an invalid rule", rule, err)
+		}
+	}
+}
+
+// TestGenerations checks that the cells of Brian's Brain (B2/S/C3) go through
+// the dying state after living, and that the dying cells do not count as
+// neighbors.
+func TestGenerations(t *testing.T) {
+	l := NewLife([]uint{2}, nil, 6, 6, 0)
+	l.SetStates(3)
+	if l.Rule().String() != "B2/S/C3" {
+		t.Errorf("rule %s, want B2/S/C3", l.Rule())
+	}
+	l.Field().Set(2, 2, true)
+	l.Field().Set(3, 2, true)
+	l.Step()
+	// The pair dies into the dying state, and gives birth to the two pairs
+	// above and below it.
+	f := l.Field()
+	for _, c := range []struct {
+		x, y int
+		st   State
+	}{{2, 2, 2}, {3, 2, 2}, {2, 1, Live}, {3, 1, Live}, {2, 3, Live}, {3, 3, Live}, {1, 2, Dead}} {
+		if got := f.State(c.x, c.y); got != c.st {
+			t.Errorf("cell %d,%d in state %d, want %d", c.x, c.y, got, c.st)
+		}
+	}
+	if f.Population() != 4 {
+		t.Errorf("population %d, want 4", f.Population())
+	}
+	l.Step()
+	// The dying cells are dead after the last state, and are not counted as
+	// neighbors: the cell left of the pair has two live neighbors only.
+	if st := l.Field().State(2, 2); st != Dead {
+		t.Errorf("cell 2,2 in state %d, want dead", st)
+	}
+	if !l.Field().Alive(1, 2) {
+		t.Error("cell 1,2 not born")
+	}
+	births, deaths := l.Turnover()
+	if births != 6 || deaths != 4 {
+		t.Errorf("%d births and %d deaths, want 6 and 4", births, deaths)
+	}
+}
+
+// TestBirthOnlyOnDeadCells checks that a live cell with a count in B but not in
+// S does not stay alive: the middle cell of a triple has two live neighbors.
+func TestBirthOnlyOnDeadCells(t *testing.T) {
+	for _, tc := range []struct {
+		rule string
+		want State
+	}{
+		{"B2/S", Dead},
+		{"B2/S/C3", 2},
+		{"B2/S2", Live},
+	} {
+		birth, survival, states, err := ParseRuleStates(tc.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := NewLife(birth, survival, 7, 7, 0)
+		l.SetStates(states)
+		for x := uint(2); x <= 4; x++ {
+			l.Field().Set(x, 3, true)
+		}
+		if got := l.NextState(3, 3); got != tc.want {
+			t.Errorf("%s: middle cell in state %d, want %d", tc.rule, got, tc.want)
+		}
+	}
+}
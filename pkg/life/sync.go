@@ -0,0 +1,84 @@
+package life
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncLife wraps a game so several goroutines can use it at once, like a
+// stepper, a web server and a goroutine gathering statistics. The reads share
+// a read lock and the changes take the write lock. Life itself assumes a single
+// goroutine, so the wrapped game must not be used directly while the wrapper
+// is in use.
+type SyncLife struct {
+	mu sync.RWMutex
+	l  *Life
+}
+
+// NewSyncLife returns a wrapper of l safe for concurrent use.
+func NewSyncLife(l *Life) *SyncLife {
+	return &SyncLife{l: l}
+}
+
+// Read calls fn with the game under the read lock, so it may run alongside
+// other reads but not alongside the changes. fn must not change the game nor
+// keep it after returning.
+func (s *SyncLife) Read(fn func(l *Life)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.l)
+}
+
+// Write calls fn with the game under the write lock, so it may change it.
+func (s *SyncLife) Write(fn func(l *Life)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.l)
+}
+
+// Step advances the game by one step under the write lock. The functions added
+// with OnStep are called under the lock too, so they must not use the
+// wrapper.
+func (s *SyncLife) Step() {
+	s.Write((*Life).Step)
+}
+
+// Snapshot returns a copy of the board of the current generation and the
+// number of that generation, which stay the same while the game goes on.
+func (s *SyncLife) Snapshot() (f *Field, gen uint64) {
+	s.Read(func(l *Life) {
+		f, gen = l.a.Copy(), l.gen
+	})
+	return f, gen
+}
+
+// Generation returns the number of steps taken.
+func (s *SyncLife) Generation() (gen uint64) {
+	s.Read(func(l *Life) { gen = l.gen })
+	return gen
+}
+
+// Run is like Life.Run, taking the write lock for every step, including the
+// ones of the Step of the options, and the read lock for every check of Until.
+// The functions of Do run without the lock, so they use Read or Write to reach
+// the game. Run being the stepper, the other goroutines must not step the game
+// nor unmarshal into it while it runs.
+func (s *SyncLife) Run(ctx context.Context, opts RunOptions) error {
+	step := opts.Step
+	if step == nil {
+		step = s.l.Step
+	}
+	opts.Step = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		step()
+	}
+	if until := opts.Until; until != nil {
+		opts.Until = func(l *Life) bool {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return until(l)
+		}
+	}
+	return s.l.Run(ctx, opts)
+}
@@ -0,0 +1,41 @@
+package life
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// TestSyncLife checks that the snapshots taken while the game runs are whole
+// generations. Run it with -race to find the data races.
+func TestSyncLife(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 32, 32, 0.5)
+	s := NewSyncLife(l)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := uint64(0)
+			for j := 0; j < 50; j++ {
+				f, gen := s.Snapshot()
+				if gen < last {
+					t.Errorf("generation %d after %d", gen, last)
+				}
+				last = gen
+				s.Read(func(l *Life) {
+					if l.Generation() == gen && !equalFields(f, l.Field()) {
+						t.Errorf("generation %d: snapshot differs from the board", gen)
+					}
+				})
+			}
+		}()
+	}
+	if err := s.Run(context.Background(), RunOptions{Generations: 200}); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if s.Generation() != 200 {
+		t.Errorf("generation %d, want 200", s.Generation())
+	}
+}
@@ -0,0 +1,65 @@
+package life
+
+import (
+	"bufio"
+	"io"
+)
+
+// RenderOptions tell WriteText how to write the board.
+type RenderOptions struct {
+	// Alive and Dead are the characters of the live and the dead cells, O
+	// and a dot if 0, like in the plaintext format.
+	Alive, Dead rune
+	// Region is the part of the board written, wrapping around the edges,
+	// or the whole board if its size is 0.
+	Region Rect
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteText writes the board of the current generation to w as text, a line
+// for every row and a character for every cell, without building it in memory
+// first. It returns the number of bytes written.
+func (l *Life) WriteText(w io.Writer, opts RenderOptions) (int64, error) {
+	alive, dead := opts.Alive, opts.Dead
+	if alive == 0 {
+		alive = 'O'
+	}
+	if dead == 0 {
+		dead = '.'
+	}
+	r := opts.Region
+	if r.W == 0 || r.H == 0 {
+		r = Rect{W: l.w, H: l.h}
+	}
+	c := &countingWriter{w: w}
+	b := bufio.NewWriter(c)
+	for y := 0; y < int(r.H); y++ {
+		for x := 0; x < int(r.W); x++ {
+			if l.a.Alive(int(r.X)+x, int(r.Y)+y) {
+				b.WriteRune(alive)
+			} else {
+				b.WriteRune(dead)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	err := b.Flush()
+	return c.n, err
+}
+
+// WriteTo writes the whole board of the current generation to w like
+// WriteText with the zero options, implementing io.WriterTo.
+func (l *Life) WriteTo(w io.Writer) (int64, error) {
+	return l.WriteText(w, RenderOptions{})
+}
@@ -0,0 +1,47 @@
+package life
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 4, 3, 0)
+	l.Field().Set(0, 0, true)
+	l.Field().Set(3, 2, true)
+	for _, tc := range []struct {
+		opts RenderOptions
+		want string
+	}{
+		{RenderOptions{}, "O...\n....\n...O\n"},
+		{RenderOptions{Alive: '█', Dead: ' '}, "█   \n    \n   █\n"},
+		// The region wraps around the edges.
+		{RenderOptions{Region: Rect{X: 3, Y: 2, W: 2, H: 2}}, "O.\n.O\n"},
+	} {
+		var b bytes.Buffer
+		n, err := l.WriteText(&b, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tc.want || n != int64(b.Len()) {
+			t.Errorf("%+v: wrote %q in %d bytes, want %q", tc.opts, b.String(), n, tc.want)
+		}
+	}
+	var b strings.Builder
+	if _, err := l.WriteTo(&b); err != nil || b.String() != "O...\n....\n...O\n" {
+		t.Errorf("WriteTo: wrote %q, %v", b.String(), err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("full") }
+
+func TestWriteTextError(t *testing.T) {
+	l := NewLife([]uint{3}, []uint{2, 3}, 4, 3, 0)
+	if n, err := l.WriteTo(failingWriter{}); err == nil || n != 0 {
+		t.Errorf("wrote %d bytes with %v, want an error", n, err)
+	}
+}
@@ -0,0 +1,39 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// Image draws the board as an image, every cell a square of Scale x Scale
+// pixels of the Alive or Dead color. A Scale of 0 is taken as 1.
+type Image struct {
+	Scale       int
+	Alive, Dead color.RGBA
+}
+
+// Render draws the viewport in the Image of the frame.
+func (m Image) Render(l *life.Life, v Viewport) Frame {
+	scale := m.Scale
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, v.Width*scale, v.Height*scale))
+	for y := 0; y < v.Height; y++ {
+		for x := 0; x < v.Width; x++ {
+			c := m.Dead
+			if l.Alive(v.X+x, v.Y+y) {
+				c = m.Alive
+			}
+			for py := y * scale; py < (y+1)*scale; py++ {
+				for px := x * scale; px < (x+1)*scale; px++ {
+					i := img.PixOffset(px, py)
+					img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+				}
+			}
+		}
+	}
+	return Frame{Image: img}
+}
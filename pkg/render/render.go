@@ -0,0 +1,48 @@
+// Package render draws the boards of package life as text or as images, so the
+// frontends share the drawing of the cells and new ones only pick a renderer:
+//
+//	frame := render.Braille.Render(l, render.Full(l))
+//	fmt.Print(frame)
+package render
+
+import (
+	"image"
+	"strings"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// Renderer draws a viewport of a board.
+type Renderer interface {
+	Render(l *life.Life, v Viewport) Frame
+}
+
+// Viewport is the region of the board drawn, in cells, from the cell at X, Y.
+// It wraps around the edges of the board like the cells do.
+type Viewport struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Full returns the viewport covering the whole board of l.
+func Full(l *life.Life) Viewport {
+	return Viewport{Width: int(l.Width()), Height: int(l.Height())}
+}
+
+// Frame is the drawing of a viewport: the rows of characters of the text
+// renderers, or the image of the image renderer.
+type Frame struct {
+	Lines []string
+	Image *image.RGBA
+}
+
+// String returns the rows of characters of the frame, each one ending with a
+// new line.
+func (f Frame) String() string {
+	var b strings.Builder
+	for _, line := range f.Lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
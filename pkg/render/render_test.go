@@ -0,0 +1,88 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// glider returns a board of 4x4 cells with a glider at its top left corner.
+func glider(t *testing.T) *life.Life {
+	t.Helper()
+	f, _, err := life.ParsePlaintext([]byte(".O\n..O\nOOO\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := life.NewLife([]uint{3}, []uint{2, 3}, 4, 4, 0)
+	l.Field().Stamp(f, 0, 0)
+	return l
+}
+
+func TestText(t *testing.T) {
+	l := glider(t)
+	for _, c := range []struct {
+		name string
+		r    Renderer
+		v    Viewport
+		want string
+	}{
+		{"ascii", ASCII, Full(l), ".#..\n..#.\n###.\n....\n"},
+		{"blocks", Blocks, Full(l), " █  \n  █ \n███ \n    \n"},
+		{"half-blocks", HalfBlocks, Full(l), " ▀▄ \n▀▀▀ \n"},
+		{"braille", Braille, Full(l), "⠬⠆\n"},
+		{"sextants", Sextants, Full(l), "🬯🬓\n  \n"},
+		// The viewport wraps around the edges of the board.
+		{"wrapped", ASCII, Viewport{X: 3, Y: 2, Width: 3, Height: 2}, ".##\n...\n"},
+	} {
+		if got := c.r.Render(l, c.v).String(); got != c.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", c.name, got, c.want)
+		}
+	}
+}
+
+// TestViewportOffsets checks that the viewports starting more than a board
+// away from the origin wrap around the torus, on a board whose sizes do not
+// divide the range of the unsigned integers.
+func TestViewportOffsets(t *testing.T) {
+	l := life.NewLife([]uint{3}, []uint{2, 3}, 5, 3, 0)
+	l.Field().Set(0, 0, true)
+	l.Field().Set(4, 2, true)
+	const want = "#....\n.#...\n.....\n"
+	for _, v := range []Viewport{
+		{X: 4, Y: 2, Width: 5, Height: 3},
+		{X: -1, Y: -1, Width: 5, Height: 3},
+		{X: -6, Y: -4, Width: 5, Height: 3},
+		{X: -16, Y: -10, Width: 5, Height: 3},
+		{X: 14, Y: 8, Width: 5, Height: 3},
+	} {
+		if got := ASCII.Render(l, v).String(); got != want {
+			t.Errorf("%+v: got\n%s\nwant\n%s", v, got, want)
+		}
+	}
+	alive, dead := color.RGBA{R: 0xff, A: 0xff}, color.RGBA{A: 0xff}
+	img := Image{Scale: 1, Alive: alive, Dead: dead}.Render(l, Viewport{X: -6, Y: -4, Width: 5, Height: 3}).Image
+	if img.RGBAAt(0, 0) != alive || img.RGBAAt(1, 1) != alive || img.RGBAAt(0, 1) != dead {
+		t.Error("image: the viewport at -6,-4 is not the one at 4,2")
+	}
+}
+
+func TestImage(t *testing.T) {
+	l := glider(t)
+	alive, dead := color.RGBA{R: 0xff, A: 0xff}, color.RGBA{A: 0xff}
+	img := Image{Scale: 2, Alive: alive, Dead: dead}.Render(l, Full(l)).Image
+	if w, h := img.Rect.Dx(), img.Rect.Dy(); w != 8 || h != 8 {
+		t.Fatalf("size %dx%d, want 8x8", w, h)
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			want := dead
+			if l.Alive(x/2, y/2) {
+				want = alive
+			}
+			if got := img.RGBAAt(x, y); got != want {
+				t.Errorf("pixel %d, %d: got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// Text draws the board with characters, every one showing the cells of a
+// block of DotsW x DotsH.
+type Text struct {
+	DotsW, DotsH int
+	// Glyph returns the character showing the given dots. Bit y*DotsW+x of
+	// mask is set when the dot at x, y is alive.
+	Glyph func(mask uint) rune
+}
+
+// The text renderers, from the densest to the one with a character per cell.
+var (
+	Braille    = Text{DotsW: 2, DotsH: 4, Glyph: BrailleGlyph}
+	Sextants   = Text{DotsW: 2, DotsH: 3, Glyph: SextantGlyph}
+	HalfBlocks = Text{DotsW: 1, DotsH: 2, Glyph: HalfBlockGlyph}
+	Blocks     = Cells('█', ' ')
+	ASCII      = Cells('#', '.')
+)
+
+// Render draws the viewport with a row of characters for every DotsH rows of
+// cells. The blocks crossing the edges of the viewport are drawn with the
+// cells inside only.
+func (t Text) Render(l *life.Life, v Viewport) Frame {
+	var lines []string
+	for y := 0; y < v.Height; y += t.DotsH {
+		var b strings.Builder
+		for x := 0; x < v.Width; x += t.DotsW {
+			var mask uint
+			for dy := 0; dy < t.DotsH && y+dy < v.Height; dy++ {
+				for dx := 0; dx < t.DotsW && x+dx < v.Width; dx++ {
+					if l.Alive(v.X+x+dx, v.Y+y+dy) {
+						mask |= 1 << (dy*t.DotsW + dx)
+					}
+				}
+			}
+			b.WriteRune(t.Glyph(mask))
+		}
+		lines = append(lines, b.String())
+	}
+	return Frame{Lines: lines}
+}
+
+// BrailleGlyph returns the braille pattern showing a block of 2x4 dots.
+// See: https://en.wikipedia.org/wiki/Braille_Patterns#Identifying,_naming_and_ordering
+func BrailleGlyph(mask uint) rune {
+	var bits uint
+	for i, b := range [8]uint{0x01, 0x08, 0x02, 0x10, 0x04, 0x20, 0x40, 0x80} {
+		if mask&(1<<i) != 0 {
+			bits |= b
+		}
+	}
+	return rune(0x2800 + bits)
+}
+
+// SextantGlyph returns the sextant showing a block of 2x3 dots. The sextants
+// block leaves out the characters already available as half blocks.
+// See: https://en.wikipedia.org/wiki/Symbols_for_Legacy_Computing
+func SextantGlyph(mask uint) rune {
+	switch mask {
+	case 0:
+		return ' '
+	case 0b010101:
+		return '▌'
+	case 0b101010:
+		return '▐'
+	case 0b111111:
+		return '█'
+	}
+	r := rune(0x1FB00 + mask - 1)
+	if mask > 0b010101 {
+		r--
+	}
+	if mask > 0b101010 {
+		r--
+	}
+	return r
+}
+
+// HalfBlockGlyph returns the half block showing a block of 1x2 dots.
+func HalfBlockGlyph(mask uint) rune {
+	return [4]rune{' ', '▀', '▄', '█'}[mask&3]
+}
+
+// Cells returns the text renderer drawing every cell with a character, the
+// given ones for the live and the dead cells.
+func Cells(alive, dead rune) Text {
+	return Text{DotsW: 1, DotsH: 1, Glyph: func(mask uint) rune {
+		if mask != 0 {
+			return alive
+		}
+		return dead
+	}}
+}
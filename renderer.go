@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kerrigan29a/go_life/pkg/render"
+)
+
+// renderer tells which characters are used to draw the board.
+type renderer int
+
+const (
+	rendererBraille renderer = iota
+	rendererBlocks
+	rendererHalfBlocks
+	rendererSextants
+	rendererASCII
+	// rendererWide and rendererEmoji draw every cell with a character two
+	// columns wide.
+	rendererWide
+	rendererEmoji
+	rendererSixel
+	rendererKitty
+	rendererITerm2
+	numRenderers
+)
+
+var rendererNames = [numRenderers]string{"braille", "blocks", "half-blocks", "sextants", "ascii", "wide", "emoji", "sixel", "kitty", "iterm2"}
+
+func (r renderer) String() string {
+	return rendererNames[r]
+}
+
+// parseRenderer returns the renderer with the given name. The name auto picks
+// the best graphics renderer supported by the terminal, or braille.
+func parseRenderer(s string) (renderer, error) {
+	if s == "auto" {
+		r := rendererBraille
+		if detectKitty() {
+			r = rendererKitty
+		} else if detectITerm2() {
+			r = rendererITerm2
+		}
+		logs.Info("renderer picked", "renderer", r, "term", os.Getenv("TERM"), "term_program", os.Getenv("TERM_PROGRAM"))
+		return r, nil
+	}
+	for i, name := range rendererNames {
+		if name == s {
+			return renderer(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid renderer: %s", s)
+}
+
+// graphics reports whether r draws the board as an image instead of with
+// characters.
+func (r renderer) graphics() bool {
+	return r >= rendererSixel
+}
+
+// span returns the number of columns taken by every character.
+func (r renderer) span() int {
+	if r == rendererWide || r == rendererEmoji {
+		return 2
+	}
+	return 1
+}
+
+// text returns the text renderer drawing the characters of r, or braille for
+// the graphics renderers, used where they draw characters.
+func (r renderer) text() render.Text {
+	switch r {
+	case rendererBlocks:
+		return render.Blocks
+	case rendererHalfBlocks:
+		return render.HalfBlocks
+	case rendererSextants:
+		return render.Sextants
+	case rendererASCII:
+		return render.ASCII
+	case rendererWide:
+		return render.Cells('＃', '．')
+	case rendererEmoji:
+		// The dead cells are an ideographic space.
+		return render.Cells('🟩', '\u3000')
+	}
+	return render.Braille
+}
+
+// dots returns the number of dots of every column of a character along each
+// axis.
+func (r renderer) dots() (w, h int) {
+	if r.graphics() {
+		return 4, 8
+	}
+	t := r.text()
+	return t.DotsW, t.DotsH
+}
+
+// glyph returns the character showing the given dots. Bit y*w+x of mask is set
+// when the dot at x, y is on.
+func (r renderer) glyph(mask uint) rune {
+	return r.text().Glyph(mask)
+}
+
+// block returns the character showing a whole cell when every cell is drawn
+// with whole characters.
+func (r renderer) block(alive bool) rune {
+	t := r.text()
+	if t.DotsW*t.DotsH > 1 {
+		t = render.Blocks
+	}
+	if alive {
+		return t.Glyph(1)
+	}
+	return t.Glyph(0)
+}
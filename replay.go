@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// replayHeader is the first line of the replay files.
+const replayHeader = "go_life replay 1"
+
+// replay holds a run recorded by -record: the flags, the seed and the size of
+// the board, and the keys and the mouse events of the user with the generation
+// where they were received. Played again, they reproduce the run.
+type replay struct {
+	args   []string
+	seed   int64
+	w, h   uint
+	events []replayEvent
+	// next is the position of the next event to play.
+	next int
+}
+
+// replayEvent is an event of the user received at the given generation.
+type replayEvent struct {
+	epoch uint
+	event tcell.Event
+}
+
+// recorder writes the events of the user to a replay file.
+type recorder struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// startRecording creates the replay file at path, writing the flags of fs, the
+// seed and the size of the board of the game.
+func startRecording(path string, fs *flag.FlagSet, g *game) *recorder {
+	f, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+	r := &recorder{f: f, w: bufio.NewWriter(f)}
+	fmt.Fprintln(r.w, replayHeader)
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "record" && f.Name != "replay" {
+			args = append(args, strconv.Quote("-"+f.Name+"="+f.Value.String()))
+		}
+	})
+	fmt.Fprintf(r.w, "args %s\n", strings.Join(args, " "))
+	fmt.Fprintf(r.w, "seed %d\n", g.seed)
+	fmt.Fprintf(r.w, "board %d %d\n", g.life.Width(), g.life.Height())
+	// The board keeps its size, so the events do the same on it.
+	g.fitWidth, g.fitHeight = false, false
+	return r
+}
+
+// record writes an event of the user received at the given generation.
+func (r *recorder) record(epoch uint, event tcell.Event) {
+	switch e := event.(type) {
+	case *tcell.EventKey:
+		fmt.Fprintf(r.w, "key %d %d %d %d\n", epoch, e.Key(), e.Rune(), e.Modifiers())
+	case *tcell.EventMouse:
+		x, y := e.Position()
+		fmt.Fprintf(r.w, "mouse %d %d %d %d %d\n", epoch, x, y, e.Buttons(), e.Modifiers())
+	}
+}
+
+// Close writes the events left and closes the replay file.
+func (r *recorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+// readReplay reads the replay file at path.
+func readReplay(path string) (*replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != replayHeader {
+		return nil, fmt.Errorf("%s: not a replay file", path)
+	}
+	r := &replay{}
+	n := 1
+	for scanner.Scan() {
+		n++
+		kind, rest, _ := strings.Cut(scanner.Text(), " ")
+		if kind == "args" {
+			for _, arg := range strings.Fields(rest) {
+				s, err := strconv.Unquote(arg)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: invalid argument: %s", path, n, arg)
+				}
+				r.args = append(r.args, s)
+			}
+			continue
+		}
+		var v []int64
+		for _, field := range strings.Fields(rest) {
+			i, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid number: %s", path, n, field)
+			}
+			v = append(v, i)
+		}
+		switch {
+		case kind == "seed" && len(v) == 1:
+			r.seed = v[0]
+		case kind == "board" && len(v) == 2 && v[0] > 0 && v[1] > 0:
+			r.w, r.h = uint(v[0]), uint(v[1])
+		case kind == "key" && len(v) == 4 && v[0] >= 0:
+			r.events = append(r.events, replayEvent{uint(v[0]), tcell.NewEventKey(tcell.Key(v[1]), rune(v[2]), tcell.ModMask(v[3]))})
+		case kind == "mouse" && len(v) == 5 && v[0] >= 0:
+			r.events = append(r.events, replayEvent{uint(v[0]), tcell.NewEventMouse(int(v[1]), int(v[2]), tcell.ButtonMask(v[3]), tcell.ModMask(v[4]))})
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid line: %s", path, n, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if r.w == 0 {
+		return nil, errors.New(path + ": the replay has no board size")
+	}
+	return r, nil
+}
+
+// playReplay plays the next event of the replay if it was received at the
+// current generation or before, reporting whether it did. Otherwise the game
+// must step, even if paused, to reach it. Once the events are over, the user
+// takes control.
+func (g *game) playReplay() bool {
+	r := g.replay
+	if r.next == len(r.events) {
+		g.replay = nil
+		g.message = fmt.Sprintf("Replay finished at generation %d", g.epoch)
+		g.dirty = true
+		return false
+	}
+	e := r.events[r.next]
+	if e.epoch > g.epoch {
+		return false
+	}
+	r.next++
+	switch event := e.event.(type) {
+	case *tcell.EventKey:
+		// The run ended quitting, which the replay leaves to the user.
+		if g.key(event) {
+			r.next = len(r.events)
+		}
+	case *tcell.EventMouse:
+		g.mouse(event)
+	}
+	g.dirty = true
+	return true
+}
@@ -0,0 +1,67 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// replayGame returns a game of the options on a simulated terminal.
+func replayGame(t *testing.T, args ...string) *game {
+	t.Helper()
+	opts, err := parseArgs("run", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(screen.Fini)
+	screen.SetSize(40, 12)
+	return newGame(screen, opts)
+}
+
+// TestReplay checks that playing a recorded run gives the same board, with the
+// recorded flags and the flags given with the replay.
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.replay")
+	const generations = 40
+	g := replayGame(t, "-rule", "HighLife", "-record", path)
+	g.recorder = startRecording(path, flagSet, g)
+	for g.epoch < generations {
+		switch g.epoch {
+		case 3:
+			g.event(tcell.NewEventMouse(4, 3, tcell.Button1, 0))
+			g.event(tcell.NewEventMouse(0, 0, tcell.ButtonNone, 0))
+		case 10:
+			g.event(tcell.NewEventKey(tcell.KeyRune, 'f', 0))
+		case 20:
+			g.event(tcell.NewEventKey(tcell.KeyRune, 'r', 0))
+		}
+		g.step()
+	}
+	if err := g.recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := replayGame(t, "-replay", path, "-fps", "5")
+	if r.replay == nil || !r.life.Rule().Equal(g.life.Rule()) {
+		t.Fatalf("the replay runs %s, want %s", r.life.Rule(), g.life.Rule())
+	}
+	for r.epoch < generations {
+		if r.replay != nil && r.playReplay() {
+			continue
+		}
+		r.step()
+	}
+	if r.life.Width() != g.life.Width() || r.life.Height() != g.life.Height() {
+		t.Fatalf("board of %dx%d, want %dx%d", r.life.Width(), r.life.Height(), g.life.Width(), g.life.Height())
+	}
+	if r.life.Field().Hash() != g.life.Field().Hash() {
+		t.Error("the replay ends on a different board")
+	}
+}
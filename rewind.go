@@ -0,0 +1,146 @@
+package main
+
+import "fmt"
+
+// rewind is a ring buffer with the last generations of the board, so they can
+// be stepped through backwards.
+type rewind struct {
+	items []snapshot
+	// start is the position of the oldest item and n the number of items.
+	start, n int
+	// budget is the number of bytes the cells of the items may use.
+	budget int
+}
+
+// push adds a copy of a generation, with its comparison board if any, dropping
+// the oldest one when the budget is spent. The fields of the dropped
+// generations are reused to save allocations.
+func (r *rewind) push(s snapshot) {
+	size := s.cells()
+	if size == 0 || r.budget < size {
+		return
+	}
+	if capacity := r.budget / size; capacity != len(r.items) {
+		// The board size changed.
+		r.items = make([]snapshot, capacity)
+		r.start, r.n = 0, 0
+	}
+	i := (r.start + r.n) % len(r.items)
+	if r.n == len(r.items) {
+		r.start = (r.start + 1) % len(r.items)
+	} else {
+		r.n++
+	}
+	// The dying states of the Generations rules are copied too.
+	old := r.items[i]
+	if old.field == nil || old.field.CopyFrom(s.field) != nil {
+		old.field = s.field.Copy()
+	}
+	switch {
+	case s.other == nil:
+		old.other = nil
+	case old.other == nil || old.other.CopyFrom(s.other) != nil:
+		old.other = s.other.Copy()
+	}
+	old.epoch = s.epoch
+	r.items[i] = old
+}
+
+// pop removes and returns the newest generation, reporting whether there was
+// any.
+func (r *rewind) pop() (snapshot, bool) {
+	if r.n == 0 {
+		return snapshot{}, false
+	}
+	r.n--
+	i := (r.start + r.n) % len(r.items)
+	// The fields stay in place to be reused, so the caller gets copies.
+	return r.items[i].copy(), true
+}
+
+// back goes to the previous generation, if it was kept.
+func (g *game) back() {
+	if !g.goBack() {
+		g.message = "No earlier generations"
+		g.draw()
+		return
+	}
+	g.restored()
+}
+
+// forth goes to the next generation, computing it unless it was left behind by
+// going back.
+func (g *game) forth() {
+	if !g.goForth() {
+		g.save()
+		g.next()
+		return
+	}
+	g.restored()
+}
+
+// seek goes back or forth to the generation at position i of the timeline,
+// which holds the kept generations, the current one and the ones left behind
+// by going back.
+func (g *game) seek(i int) {
+	moved := false
+	for g.rewind.n > i && g.goBack() {
+		moved = true
+	}
+	for g.rewind.n < i && g.goForth() {
+		moved = true
+	}
+	if moved {
+		g.restored()
+	}
+}
+
+// goBack replaces the board with the previous generation without drawing it,
+// reporting whether it was kept.
+func (g *game) goBack() bool {
+	s, ok := g.rewind.pop()
+	if !ok {
+		return false
+	}
+	g.forward = append(g.forward, g.snapshot())
+	g.setBoards(s)
+	return true
+}
+
+// goForth replaces the board with the next generation left behind by going
+// back without drawing it, reporting whether there was any.
+func (g *game) goForth() bool {
+	if len(g.forward) == 0 {
+		return false
+	}
+	g.rewind.push(g.boards())
+	s := g.forward[len(g.forward)-1]
+	g.forward = g.forward[:len(g.forward)-1]
+	g.setBoards(s)
+	return true
+}
+
+// goTo goes to generation n: back through the kept generations if it is one of
+// them, or forth computing the generations in between without drawing them.
+func (g *game) goTo(n uint) error {
+	if n < g.epoch {
+		r := &g.rewind
+		for i := r.n - 1; i >= 0; i-- {
+			if r.items[(r.start+i)%len(r.items)].epoch == n {
+				g.seek(i)
+				return nil
+			}
+		}
+		return fmt.Errorf("generation %d is no longer kept", n)
+	}
+	for g.epoch < n && g.goForth() {
+	}
+	if g.epoch < n {
+		g.save()
+	}
+	for g.epoch < n {
+		g.step()
+	}
+	g.restored()
+	return nil
+}
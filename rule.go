@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// newLife returns a new empty game with the rule r, including its number of
+// states.
+func newLife(r life.Rule, w, h uint) *life.Life {
+	l := life.NewLife(r.Birth, r.Survival, w, h, 0)
+	l.SetRule(r)
+	return l
+}
+
+// ruleString returns the rule in B/S notation, or nothing if it is not set.
+func ruleString(r life.Rule) string {
+	if r.Birth == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// ruleLabel returns the rule in B/S notation, followed by its name if it has
+// one, like B36/S23 (HighLife).
+func ruleLabel(r life.Rule) string {
+	if name := r.Name(); name != "" {
+		return fmt.Sprintf("%s (%s)", r, name)
+	}
+	return r.String()
+}
+
+// ruleFlag is the value of a flag taking a rule by name or in either notation.
+// The rule is checked when the flag is set, so a malformed one is reported like
+// any other invalid value of a flag.
+type ruleFlag struct {
+	r    *life.Rule
+	name string
+	// given holds the rules given to the flags sharing r since the last layer
+	// of flags started, by name, to reject the ones conflicting.
+	given map[string]string
+}
+
+// ruleFlags defines flags of the given names setting r, aliases of each other.
+// Giving two of them different rules is an error.
+func ruleFlags(fs *flag.FlagSet, r *life.Rule, usage string, names ...string) {
+	given := map[string]string{}
+	for i, name := range names {
+		help := usage
+		if i > 0 {
+			help = fmt.Sprintf("`Rule`, alias of -%s", names[0])
+		}
+		fs.Var(&ruleFlag{r: r, name: name, given: given}, name, help)
+	}
+}
+
+func (f *ruleFlag) String() string {
+	if f.r == nil {
+		return ""
+	}
+	return ruleString(*f.r)
+}
+
+func (f *ruleFlag) Set(s string) error {
+	r, err := life.ParseNamedRule(s)
+	if err != nil {
+		return err
+	}
+	for name, other := range f.given {
+		if name != f.name && other != r.String() {
+			return fmt.Errorf("conflicts with -%s %s", name, other)
+		}
+	}
+	if f.given != nil {
+		f.given[f.name] = r.String()
+	}
+	*f.r = r
+	return nil
+}
+
+// startLayer makes the rule flags of fs forget the flags given so far, so the
+// ones of the configuration file, the environment and the command line
+// override each other instead of conflicting.
+func startLayer(fs *flag.FlagSet) {
+	fs.VisitAll(func(fl *flag.Flag) {
+		if f, ok := fl.Value.(*ruleFlag); ok {
+			for name := range f.given {
+				delete(f.given, name)
+			}
+		}
+	})
+}
+
+// rulesFlag is the value of a flag taking comma-separated rules by name or in
+// either notation, checked when the flag is set.
+type rulesFlag struct {
+	rules *[]life.Rule
+}
+
+func (f rulesFlag) String() string {
+	if f.rules == nil {
+		return ""
+	}
+	s := make([]string, len(*f.rules))
+	for i, r := range *f.rules {
+		s[i] = r.String()
+	}
+	return strings.Join(s, ",")
+}
+
+func (f rulesFlag) Set(s string) error {
+	var rules []life.Rule
+	for _, item := range strings.Split(s, ",") {
+		r, err := life.ParseNamedRule(strings.TrimSpace(item))
+		if err != nil {
+			return err
+		}
+		rules = append(rules, r)
+	}
+	*f.rules = rules
+	return nil
+}
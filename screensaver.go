@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// settleWindow is the number of generations whose populations must repeat for
+// the board to be settled.
+const settleWindow = 200
+
+// maxSettlePeriod is the longest period of the populations of a settled board.
+// It is the period of the populations, so it also takes the spaceships flying
+// around the board.
+const maxSettlePeriod = 30
+
+// screensaver holds the settings of the screensaver mode, which reseeds the
+// board when it dies out or settles.
+type screensaver struct {
+	// rules holds the rules cycled on every restart, if any.
+	rules []life.Rule
+	// themes reports whether the themes are cycled on every restart.
+	themes   bool
+	restarts int
+}
+
+// settled reports whether the populations of the last generations repeat with
+// a short period, which happens when only still lifes, oscillators and
+// spaceships are left.
+func (g *game) settled() bool {
+	if g.epoch < settleWindow || len(g.populations) < settleWindow {
+		return false
+	}
+	pops := g.populations[len(g.populations)-settleWindow:]
+	for p := 1; p <= maxSettlePeriod; p++ {
+		periodic := true
+		for i := p; i < len(pops) && periodic; i++ {
+			periodic = pops[i] == pops[i-p]
+		}
+		if periodic {
+			return true
+		}
+	}
+	return false
+}
+
+// restart reseeds the board if the screensaver is on and the board died out or
+// settled, moving to the next rule and theme if they are cycled.
+func (g *game) restart() {
+	s := g.screensaver
+	if s == nil || (g.populations[len(g.populations)-1] > 0 && !g.settled()) {
+		return
+	}
+	s.restarts++
+	if len(s.rules) > 0 {
+		r := s.rules[(s.restarts-1)%len(s.rules)]
+		g.life.SetRule(r)
+	}
+	if s.themes {
+		g.setTheme(themes[(g.themeIndex()+1)%len(themes)])
+	}
+	g.message = fmt.Sprintf("Restart %d with %s", s.restarts, ruleLabel(g.life.Rule()))
+	g.reseed()
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+	"golang.org/x/exp/slices"
+)
+
+// soupSize is the width and height of the random soups of the search.
+const soupSize = 16
+
+// searchSize is the width and height of the board where the soups start. The
+// spaceships are taken out when they reach its edges, before wrapping around,
+// and the board doubles when any other object does, up to maxSearchSize.
+const (
+	searchSize    = 64
+	maxSearchSize = 512
+)
+
+// maxSoupGens is the number of generations a soup may take to settle.
+const maxSoupGens = 5000
+
+// maxCensusPeriod is the longest period of the objects told apart by the
+// census, and of the settled boards.
+const maxCensusPeriod = 60
+
+// wechslerDigits holds the digits of the extended Wechsler format, and the
+// lengths of the runs of empty columns after a y.
+const wechslerDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// wechsler returns f in the extended Wechsler format: strips of five rows
+// separated by z, with a digit for every column and w, x or y standing for
+// runs of empty columns.
+// See: https://conwaylife.com/wiki/Apgcode
+func wechsler(f *life.Field) string {
+	var b strings.Builder
+	for y0 := 0; y0 < int(f.Height()); y0 += 5 {
+		if y0 > 0 {
+			b.WriteByte('z')
+		}
+		zeros := 0
+		for x := 0; x < int(f.Width()); x++ {
+			v := 0
+			for dy := 0; dy < 5 && y0+dy < int(f.Height()); dy++ {
+				if f.Alive(x, y0+dy) {
+					v |= 1 << dy
+				}
+			}
+			if v == 0 {
+				zeros++
+				continue
+			}
+			for zeros > 0 {
+				switch {
+				case zeros >= 4:
+					n := zeros
+					if n > 39 {
+						n = 39
+					}
+					b.WriteByte('y')
+					b.WriteByte(wechslerDigits[n-4])
+					zeros -= n
+				case zeros == 3:
+					b.WriteByte('x')
+					zeros = 0
+				case zeros == 2:
+					b.WriteByte('w')
+					zeros = 0
+				default:
+					b.WriteByte('0')
+					zeros = 0
+				}
+			}
+			b.WriteByte(wechslerDigits[v])
+		}
+	}
+	return b.String()
+}
+
+// apgcode returns the code of the object f under the given rule: xs with the
+// population for still lifes, xp with the period for oscillators and xq with
+// the period for spaceships, followed by the shortest and then alphabetically
+// first extended Wechsler code of all its phases and orientations. The objects
+// not coming back to their first shape within maxCensusPeriod generations are
+// zz_UNKNOWN.
+func apgcode(f *life.Field, rule life.Rule) string {
+	margin := uint(maxCensusPeriod + 2)
+	l := newLife(rule, f.Width()+2*margin, f.Height()+2*margin)
+	l.Field().Stamp(f, int(margin), int(margin))
+	var phases []*life.Field
+	var start life.Rect
+	for gen := 0; gen <= maxCensusPeriod; gen++ {
+		r, ok := l.Field().BoundingBox()
+		if !ok {
+			return "zz_UNKNOWN"
+		}
+		phase := l.Field().Crop(r)
+		if gen == 0 {
+			start = r
+		} else if shapeKey(phase) == shapeKey(phases[0]) {
+			prefix := fmt.Sprintf("xp%d", gen)
+			switch {
+			case r.X != start.X || r.Y != start.Y:
+				prefix = fmt.Sprintf("xq%d", gen)
+			case gen == 1:
+				prefix = fmt.Sprintf("xs%d", phase.Population())
+			}
+			return prefix + "_" + canonicalWechsler(phases)
+		}
+		phases = append(phases, phase)
+		l.Step()
+	}
+	return "zz_UNKNOWN"
+}
+
+// canonicalWechsler returns the shortest and then alphabetically first
+// extended Wechsler code of the phases in their eight orientations.
+func canonicalWechsler(phases []*life.Field) string {
+	best := ""
+	for _, p := range phases {
+		f := p
+		for i := 0; i < 8; i++ {
+			if i == 4 {
+				f = f.Copy()
+				f.FlipHorizontal()
+			}
+			if w := wechsler(f); best == "" || len(w) < len(best) || (len(w) == len(best) && w < best) {
+				best = w
+			}
+			f = f.Rotate()
+		}
+	}
+	return best
+}
+
+// census holds the objects found by a search.
+type census struct {
+	// soups is the number of soups run, and unsettled the ones that did not
+	// settle or grew out of the board.
+	soups, unsettled uint
+	counts           map[string]uint
+	// samples holds the number of the first soup where every object was
+	// found.
+	samples map[string]uint
+}
+
+func newCensus() *census {
+	return &census{counts: map[string]uint{}, samples: map[string]uint{}}
+}
+
+// add counts the objects of the soup of the given number.
+func (c *census) add(codes []string, settled bool, soup uint) {
+	c.soups++
+	if !settled {
+		c.unsettled++
+	}
+	for _, code := range codes {
+		c.counts[code]++
+		if _, ok := c.samples[code]; !ok {
+			c.samples[code] = soup
+		}
+	}
+}
+
+// merge adds the objects of other, keeping the samples of the first soups.
+func (c *census) merge(other *census) {
+	c.soups += other.soups
+	c.unsettled += other.unsettled
+	for code, n := range other.counts {
+		c.counts[code] += n
+		if s, ok := c.samples[code]; !ok || other.samples[code] < s {
+			c.samples[code] = other.samples[code]
+		}
+	}
+}
+
+// randomSoup returns the soup of the given seed, with every cell alive with the
+// probability of density.
+func randomSoup(seed int64, density float64) *life.Field {
+	rng := rand.New(rand.NewSource(seed))
+	f := life.NewField(soupSize, soupSize)
+	for y := uint(0); y < soupSize; y++ {
+		for x := uint(0); x < soupSize; x++ {
+			f.Set(x, y, rng.Float64() < density)
+		}
+	}
+	return f
+}
+
+// runSoup runs the soup until it settles, taking out the spaceships that reach
+// the edges of the board, and returns the codes of its objects. It reports
+// whether the soup settled, which it does not when it grows out of
+// maxSearchSize or runs for maxSoupGens generations.
+func runSoup(soup *life.Field, rule life.Rule) ([]string, bool) {
+	l := newLife(rule, searchSize, searchSize)
+	l.Field().Stamp(soup, (searchSize-int(soup.Width()))/2, (searchSize-int(soup.Height()))/2)
+	var codes []string
+	// seen holds the generation of the hashes of the last boards.
+	seen := map[uint64]int{}
+	for gen := 0; gen < maxSoupGens; gen++ {
+		l.Step()
+		escaped, ok := takeEscapees(l)
+		codes = append(codes, escaped...)
+		if !ok {
+			if l.Width()*2 > maxSearchSize {
+				return codes, false
+			}
+			l.Resize(l.Width()*2, l.Height()*2, life.Center)
+			seen = map[uint64]int{}
+			continue
+		}
+		h := l.Field().Hash()
+		if last, ok := seen[h]; ok && gen-last <= maxCensusPeriod {
+			for _, o := range splitObjects(l.Field()) {
+				codes = append(codes, apgcode(o.field, rule))
+			}
+			return codes, true
+		}
+		seen[h] = gen
+		if len(seen) > 4*maxCensusPeriod {
+			for k, g := range seen {
+				if gen-g > maxCensusPeriod {
+					delete(seen, k)
+				}
+			}
+		}
+	}
+	return codes, false
+}
+
+// takeEscapees takes out of the board of l the spaceships reaching its edges,
+// returning their codes. It reports whether all the objects there were
+// spaceships, since any other one means the soup grew out of the board. The
+// board is square.
+func takeEscapees(l *life.Life) ([]string, bool) {
+	n := int(l.Width())
+	edge := false
+	for i := 0; i < n && !edge; i++ {
+		edge = l.Field().Alive(i, 0) || l.Field().Alive(i, 1) || l.Field().Alive(i, n-2) || l.Field().Alive(i, n-1) ||
+			l.Field().Alive(0, i) || l.Field().Alive(1, i) || l.Field().Alive(n-2, i) || l.Field().Alive(n-1, i)
+	}
+	if !edge {
+		return nil, true
+	}
+	var codes []string
+	for _, o := range splitObjects(l.Field()) {
+		if o.x > 1 && o.y > 1 && o.x+int(o.field.Width()) < n-1 && o.y+int(o.field.Height()) < n-1 {
+			continue
+		}
+		code := apgcode(o.field, l.Rule())
+		if !strings.HasPrefix(code, "xq") {
+			return codes, false
+		}
+		codes = append(codes, code)
+		o.field.ForEachAlive(func(x, y int) {
+			l.Field().Set(uint(o.x+x), uint(o.y+y), false)
+		})
+	}
+	return codes, true
+}
+
+// search runs the given number of soups, made by soup from their numbers,
+// splitting them among the workers, and returns the census of their objects.
+func search(soups uint, soup func(n uint) *life.Field, workers int, rule life.Rule) *census {
+	numbers := make(chan uint)
+	results := make(chan *census)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := newCensus()
+			for n := range numbers {
+				start := time.Now()
+				codes, settled := runSoup(soup(n), rule)
+				logs.Debug("soup", "number", n, "objects", len(codes), "settled", settled, "elapsed", time.Since(start))
+				c.add(codes, settled, n)
+			}
+			results <- c
+		}()
+	}
+	go func() {
+		for i := uint(0); i < soups; i++ {
+			numbers <- i
+		}
+		close(numbers)
+		wg.Wait()
+		close(results)
+	}()
+	total := newCensus()
+	for c := range results {
+		total.merge(c)
+	}
+	return total
+}
+
+// rareShare is the largest share of the soups where a rare object shows up.
+const rareShare = 0.001
+
+// write writes the objects of the census from the most common, with the names
+// of the ones in the library, and then the rare ones with the id of a soup
+// where they were found, given by soupID. It returns the error writing them.
+func (c *census) write(w io.Writer, rule life.Rule, soupID func(n uint) string) error {
+	b := bufio.NewWriter(w)
+	names := map[string]string{}
+	if rule.Equal(labelRule) {
+		names = libraryCodes()
+	}
+	codes := c.codes()
+	fmt.Fprintf(b, "%d soups of %dx%d cells with %s, %d unsettled, %d objects\n\n", c.soups, soupSize, soupSize, rule, c.unsettled, c.objects())
+	for _, code := range codes {
+		fmt.Fprintf(b, "%10d  %-24s %s\n", c.counts[code], code, names[code])
+	}
+	var rare []string
+	for _, code := range codes {
+		if float64(c.counts[code]) <= rareShare*float64(c.soups) {
+			rare = append(rare, code)
+		}
+	}
+	if len(rare) == 0 {
+		return b.Flush()
+	}
+	sort.Strings(rare)
+	fmt.Fprintf(b, "\nRare objects, in up to %g%% of the soups, with a soup where they were found\n\n", rareShare*100)
+	for _, code := range rare {
+		fmt.Fprintf(b, "%-24s %-20s %s\n", code, soupID(c.samples[code]), names[code])
+	}
+	return b.Flush()
+}
+
+// codes returns the codes of the objects of the census from the most common.
+func (c *census) codes() []string {
+	codes := make([]string, 0, len(c.counts))
+	for code := range c.counts {
+		codes = append(codes, code)
+	}
+	slices.SortFunc(codes, func(a, b string) bool {
+		if c.counts[a] != c.counts[b] {
+			return c.counts[a] > c.counts[b]
+		}
+		return a < b
+	})
+	return codes
+}
+
+// objects returns the number of objects of the census.
+func (c *census) objects() uint {
+	total := uint(0)
+	for _, n := range c.counts {
+		total += n
+	}
+	return total
+}
+
+// libraryCodes returns the names of the patterns of the library by apgcode.
+func libraryCodes() map[string]string {
+	names := map[string]string{}
+	for _, p := range library {
+		if p.Width > maxLabelSize || p.Height > maxLabelSize {
+			continue
+		}
+		code := apgcode(p.Field(), labelRule)
+		if _, ok := names[code]; !ok && !strings.HasPrefix(code, "zz") {
+			names[code] = p.Name
+		}
+	}
+	return names
+}
+
+// runSearch runs the soup search of the options and writes the census.
+func runSearch(opts options, w io.Writer) error {
+	workers := int(opts.workers)
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	cg := opts.catagolue
+	if cg.submit && cg.root == "" {
+		cg.root = randomRoot()
+	}
+	soup := func(n uint) *life.Field { return randomSoup(opts.seed+int64(n), opts.density) }
+	soupID := func(n uint) string { return strconv.FormatInt(opts.seed+int64(n), 10) }
+	if cg.root != "" {
+		soup = func(n uint) *life.Field { return hashSoup(cg.soupID(n)) }
+		soupID = cg.soupID
+	}
+	start := time.Now()
+	c := search(opts.soups, soup, workers, opts.rule)
+	logs.Info("search done", "soups", c.soups, "unsettled", c.unsettled, "workers", workers, "elapsed", time.Since(start))
+	if err := c.write(w, opts.rule, soupID); err != nil {
+		return err
+	}
+	if cg.submit {
+		if err := cg.submitHaul(c, opts.rule); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "\nSubmitted the haul of %s to %s\n", cg.root, cg.url)
+		return err
+	}
+	return nil
+}
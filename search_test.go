@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// parseCells returns the field of the rows of a plaintext pattern, separated by
+// slashes.
+func parseCells(t *testing.T, rows string) *life.Field {
+	t.Helper()
+	f, _, err := life.ParsePlaintext([]byte(strings.ReplaceAll(rows, "/", "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestWechsler(t *testing.T) {
+	for rows, want := range map[string]string{
+		"OO/OO":              "33",
+		"OOO":                "111",
+		"O/O/O":              "7",
+		"O...O":              "1x1",
+		"O.....O":            "1y11",
+		"O/./././././O":      "1z2",
+		".O/O.O/.O/./././OO": "252z22",
+	} {
+		if got := wechsler(parseCells(t, rows)); got != want {
+			t.Errorf("%s: got %s, want %s", rows, got, want)
+		}
+	}
+}
+
+// TestApgcode checks the codes of well known objects, as listed by Catagolue.
+func TestApgcode(t *testing.T) {
+	life3, _ := life.ParseNamedRule("Life")
+	highLife, _ := life.ParseNamedRule("HighLife")
+	for _, tc := range []struct {
+		rows string
+		rule life.Rule
+		want string
+	}{
+		{"OO/OO", life3, "xs4_33"},
+		{"OOO", life3, "xp2_7"},
+		{"O/O/O", life3, "xp2_7"},
+		{".OO./O..O/.OO.", life3, "xs6_696"},
+		{".OO./O..O/.O.O/..O.", life3, "xs7_2596"},
+		{"OO./O.O/.O.", life3, "xs5_253"},
+		{".O./O.O/.O.", life3, "xs4_252"},
+		{"OO./O.O/.OO", life3, "xs6_356"},
+		{".OO./O..O/O..O/.OO.", life3, "xs8_6996"},
+		{".O./..O/OOO", life3, "xq4_153"},
+		{".OOO/OOO.", life3, "xp2_7e"},
+		{"OO../OO../..OO/..OO", life3, "xp2_318c"},
+		{".O..O/O..../O...O/OOOO.", life3, "xq4_6frc"},
+		{"OO/OO", highLife, "xs4_33"},
+		{"O", life3, "zz_UNKNOWN"},
+	} {
+		if got := apgcode(parseCells(t, tc.rows), tc.rule); got != tc.want {
+			t.Errorf("%s with %s: got %s, want %s", tc.rows, tc.rule, got, tc.want)
+		}
+	}
+}
+
+// TestCanonicalWechsler checks that every orientation of an object gets the
+// same code.
+func TestCanonicalWechsler(t *testing.T) {
+	f := parseCells(t, ".O./..O/OOO")
+	want := canonicalWechsler([]*life.Field{f})
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			f = f.Copy()
+			f.FlipVertical()
+		}
+		if got := canonicalWechsler([]*life.Field{f}); got != want {
+			t.Errorf("orientation %d: got %s, want %s", i, got, want)
+		}
+		f = f.Rotate()
+	}
+	if want != "153" {
+		t.Errorf("got %s, want 153", want)
+	}
+}
+
+func TestRunSoup(t *testing.T) {
+	life3, _ := life.ParseNamedRule("Life")
+	seeds, _ := life.ParseNamedRule("Seeds")
+	brain, _ := life.ParseNamedRule("Brian's Brain")
+	for _, tc := range []struct {
+		rows    string
+		rule    life.Rule
+		want    string
+		settled bool
+	}{
+		{"OO....../OO....../......../......../......../.....OOO", life3, "xp2_7 xs4_33", true},
+		// The glider escapes and is taken out at the edges.
+		{".O./..O/OOO", life3, "xq4_153", true},
+		// The diagonal pair of Seeds gives birth to the other diagonal
+		// and back, while in Brian's Brain its dying cells are not born
+		// again, so it dies out.
+		{"O/.O", seeds, "xp2_12", true},
+		{"O/.O", brain, "", true},
+	} {
+		codes, settled := runSoup(parseCells(t, tc.rows), tc.rule)
+		sort.Strings(codes)
+		if got := strings.Join(codes, " "); got != tc.want || settled != tc.settled {
+			t.Errorf("%s with %s: got %q, %v, want %q, %v", tc.rows, tc.rule, got, settled, tc.want, tc.settled)
+		}
+	}
+}
+
+// TestRunSearch checks that the census of the soups does not depend on the
+// number of workers.
+func TestRunSearch(t *testing.T) {
+	rule, _ := life.ParseNamedRule("Life")
+	var census [2]bytes.Buffer
+	for i, workers := range []uint{1, 3} {
+		opts := options{rule: rule, density: 0.5, seed: 1, soups: 3, workers: workers}
+		if err := runSearch(opts, &census[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.HasPrefix(census[0].String(), "3 soups of 16x16 cells with B3/S23") {
+		t.Errorf("got %q", census[0].String())
+	}
+	if census[0].String() != census[1].String() {
+		t.Errorf("the census changed with the workers:\n%s\n%s", census[0].String(), census[1].String())
+	}
+}
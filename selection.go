@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// selection is a rectangular region of the board chosen by the user.
+type selection struct {
+	// anchor reports whether the first corner has been chosen.
+	anchor bool
+	// ax, ay and cx, cy hold the board positions of two opposite corners.
+	ax, ay, cx, cy int
+}
+
+// bounds returns the region of the selection in viewport coordinates.
+func (g *game) bounds() (x0, y0, x1, y1 int) {
+	ax, ay := wrap(g.sel.ax-g.viewX, int(g.life.Width())), wrap(g.sel.ay-g.viewY, int(g.life.Height()))
+	cx, cy := wrap(g.sel.cx-g.viewX, int(g.life.Width())), wrap(g.sel.cy-g.viewY, int(g.life.Height()))
+	if ax > cx {
+		ax, cx = cx, ax
+	}
+	if ay > cy {
+		ay, cy = cy, ay
+	}
+	return ax, ay, cx + 1, cy + 1
+}
+
+// startSelection enters the selection mode. In the edit mode the selection
+// starts at the cursor, otherwise it starts with the next click.
+func (g *game) startSelection() {
+	g.sel = &selection{}
+	if g.editing {
+		g.sel.anchor = true
+		g.sel.ax, g.sel.ay = g.cursorX, g.cursorY
+		g.sel.cx, g.sel.cy = g.cursorX, g.cursorY
+	}
+	g.draw()
+}
+
+// selectAt moves the corner of the selection to the given viewport position,
+// placing the anchor there too when start is set.
+func (g *game) selectAt(x, y int, start bool) {
+	// Positions past the edges of a board smaller than the screen stay on
+	// them, instead of wrapping around.
+	if x >= int(g.life.Width()) {
+		x = int(g.life.Width()) - 1
+	}
+	if y >= int(g.life.Height()) {
+		y = int(g.life.Height()) - 1
+	}
+	p := g.toBoard(x, y)
+	if start {
+		g.sel.anchor = true
+		g.sel.ax, g.sel.ay = p.X, p.Y
+	}
+	g.sel.cx, g.sel.cy = p.X, p.Y
+}
+
+// selectedCells returns a copy of the cells inside the selection.
+func (g *game) selectedCells() *life.Field {
+	x0, y0, x1, y1 := g.bounds()
+	f := life.NewField(uint(x1-x0), uint(y1-y0))
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			f.Set(uint(x-x0), uint(y-y0), g.alive(x, y))
+		}
+	}
+	return f
+}
+
+// clearSelected kills all the cells inside the selection.
+func (g *game) clearSelected() {
+	x0, y0, x1, y1 := g.bounds()
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			p := g.toBoard(x, y)
+			g.life.Field().Set(uint(p.X), uint(p.Y), false)
+		}
+	}
+}
+
+// selectionKey handles the keys of the selection mode and reports whether the
+// key was consumed.
+func (g *game) selectionKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.sel = nil
+	case tcell.KeyRune:
+		if !g.sel.anchor {
+			return false
+		}
+		switch event.Rune() {
+		case 'y':
+			g.clipboard = g.selectedCells()
+			g.sel = nil
+		case 'd':
+			g.clipboard = g.selectedCells()
+			g.save()
+			g.clearSelected()
+			g.sel = nil
+		case 'w':
+			g.export(g.selectedCells())
+			g.sel = nil
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	g.draw()
+	return true
+}
+
+// export writes f to a new plaintext file in the current directory.
+func (g *game) export(f *life.Field) {
+	name := fmt.Sprintf("go_life-%s.cells", time.Now().Format("20060102-150405"))
+	file, err := os.Create(name)
+	if err == nil {
+		err = life.WritePlaintext(file, name, f)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		g.message = err.Error()
+		return
+	}
+	g.message = "Saved " + name
+}
+
+// paste enters the stamp mode with the contents of the clipboard.
+func (g *game) paste() {
+	if g.clipboard == nil {
+		return
+	}
+	g.stamp = life.NewPattern(g.clipboard, "")
+	g.draw()
+}
+
+// drawSelection highlights the characters inside the selection.
+func (g *game) drawSelection(cols, rows int) {
+	x0, y0, x1, y1 := g.bounds()
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			cx, cy, cw, ch := g.cellsAt(x, y)
+			if cx >= int(g.life.Width()) || cy >= int(g.life.Height()) {
+				continue
+			}
+			if cx < x1 && cx+cw > x0 && cy < y1 && cy+ch > y0 {
+				r, _, style, _ := g.screen.GetContent(x, y)
+				g.screen.SetContent(x, y, r, nil, style.Background(g.theme.selection))
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// writeSixel writes img with the sixel graphics of DEC terminals.
+// See: https://vt100.net/docs/vt3xx-gp/chapter14.html
+func writeSixel(w io.Writer, img *image.RGBA) error {
+	b := bufio.NewWriter(w)
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	// Sixel terminals have at least 256 color registers. Images with more
+	// colors are reduced to 3 bits of red and green and 2 of blue.
+	palette := make(map[color.RGBA]int)
+	key := func(c color.RGBA) color.RGBA { return c }
+	for i := 0; i < len(img.Pix); i += 4 {
+		c := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 0xff}
+		if _, ok := palette[c]; !ok {
+			palette[c] = len(palette)
+		}
+		if len(palette) > 256 {
+			key = func(c color.RGBA) color.RGBA { return color.RGBA{c.R & 0xe0, c.G & 0xe0, c.B & 0xc0, 0xff} }
+			break
+		}
+	}
+	if len(palette) > 256 {
+		palette = make(map[color.RGBA]int)
+		for i := 0; i < len(img.Pix); i += 4 {
+			c := key(color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 0xff})
+			if _, ok := palette[c]; !ok {
+				palette[c] = len(palette)
+			}
+		}
+	}
+
+	// The second parameter keeps the pixels of color 0 instead of leaving
+	// them transparent.
+	fmt.Fprintf(b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for c, i := range palette {
+		fmt.Fprintf(b, "#%d;2;%d;%d;%d", i, int(c.R)*100/255, int(c.G)*100/255, int(c.B)*100/255)
+	}
+	// Every band of six rows is written color by color, going back to the
+	// start of the band with $.
+	bands := make(map[int][]byte)
+	for y0 := 0; y0 < height; y0 += 6 {
+		for i := range bands {
+			delete(bands, i)
+		}
+		var order []int
+		for y := y0; y < y0+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				o := img.PixOffset(x, y)
+				i := palette[key(color.RGBA{img.Pix[o], img.Pix[o+1], img.Pix[o+2], 0xff})]
+				band, ok := bands[i]
+				if !ok {
+					band = make([]byte, width)
+					bands[i] = band
+					order = append(order, i)
+				}
+				band[x] |= 1 << (y - y0)
+			}
+		}
+		for n, i := range order {
+			if n > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(b, "#%d", i)
+			writeSixelRuns(b, bands[i])
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.Flush()
+}
+
+// writeSixelRuns writes a row of sixels, compressing the repeated ones.
+func writeSixelRuns(b *bufio.Writer, band []byte) {
+	for x := 0; x < len(band); {
+		n := 1
+		for x+n < len(band) && band[x+n] == band[x] {
+			n++
+		}
+		c := band[x] + '?'
+		if n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, c)
+		} else {
+			for i := 0; i < n; i++ {
+				b.WriteByte(c)
+			}
+		}
+		x += n
+	}
+}
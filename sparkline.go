@@ -0,0 +1,72 @@
+package main
+
+import "fmt"
+
+// maxPopulations is the number of generations kept for the sparkline.
+const maxPopulations = 500
+
+// sparkRows is the height of the sparkline panel, without its title.
+const sparkRows = 3
+
+// sparkBars holds the characters filling the eighths of a row.
+var sparkBars = [...]rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// record saves the population of the current generation for the sparkline.
+func (g *game) record() {
+	g.populations = appendLast(g.populations, g.life.Field().Population())
+}
+
+// drawSparkline draws the population of the last generations in the bottom
+// right corner of the board, one generation per column and scaled between the
+// smallest and largest population shown.
+func (g *game) drawSparkline(cols, rows int) {
+	w := cols / 3
+	if w > maxPopulations {
+		w = maxPopulations
+	}
+	if w < 10 || rows < sparkRows+1 {
+		return
+	}
+	pops := g.populations
+	if len(pops) > w {
+		pops = pops[len(pops)-w:]
+	}
+	lo, hi := bounds(pops)
+	x0, y0 := cols-w, rows-sparkRows-1
+	style := g.theme.status
+	g.drawText(x0, y0, cols, style, fmt.Sprintf(" Pop %d-%d", lo, hi))
+	for y := y0 + 1; y < rows; y++ {
+		g.drawText(x0, y, cols, style, "")
+	}
+	g.drawBars(x0, rows-1, w, sparkRows, pops)
+}
+
+// drawBars draws the last values fitting in w columns as bars h rows high,
+// right aligned with column x0+w-1 and standing on row bottom. The bars are
+// scaled between the smallest and largest value drawn.
+func (g *game) drawBars(x0, bottom, w, h int, values []uint) {
+	if len(values) > w {
+		values = values[len(values)-w:]
+	}
+	lo, hi := bounds(values)
+	style := g.theme.status
+	for i, v := range values {
+		// The smallest values still get the lowest bar, so the line is
+		// visible.
+		eighths := 1
+		if hi > lo {
+			eighths = 1 + int((v-lo)*uint(h*8-1)/(hi-lo))
+		}
+		x := x0 + w - len(values) + i
+		for row := 0; row < h; row++ {
+			n := eighths - row*8
+			if n > 8 {
+				n = 8
+			}
+			if n < 0 {
+				n = 0
+			}
+			g.screen.SetCell(x, bottom-row, style, sparkBars[n])
+		}
+	}
+}
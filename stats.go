@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// stats holds the births, deaths and step times of the last generations, shown
+// by the statistics screen. Like the populations, the last maxPopulations
+// generations are kept.
+type stats struct {
+	births, deaths []uint
+	elapsed        []time.Duration
+}
+
+// add saves the changes of a step.
+func (s *stats) add(st life.StepStats) {
+	s.births = appendLast(s.births, st.Births)
+	s.deaths = appendLast(s.deaths, st.Deaths)
+}
+
+// timed saves the time a step took.
+func (s *stats) timed(elapsed time.Duration) {
+	s.elapsed = append(s.elapsed, elapsed)
+	if len(s.elapsed) > maxPopulations {
+		s.elapsed = s.elapsed[len(s.elapsed)-maxPopulations:]
+	}
+}
+
+// appendLast appends v to values, keeping the last maxPopulations values.
+func appendLast(values []uint, v uint) []uint {
+	values = append(values, v)
+	if len(values) > maxPopulations {
+		values = values[len(values)-maxPopulations:]
+	}
+	return values
+}
+
+// lastValue returns the last of values, or 0 if there are none.
+func lastValue(values []uint) uint {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+// bounds returns the smallest and largest of values, or zeros if there are
+// none.
+func bounds(values []uint) (lo, hi uint) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	lo = ^uint(0)
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// average returns the mean of durations, or 0 if there are none.
+func average(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	return sum / time.Duration(len(durations))
+}
+
+// toggleStats switches between the board and the statistics screen.
+func (g *game) toggleStats() {
+	g.showStats = !g.showStats
+	if g.renderer.graphics() {
+		g.clearImages()
+	}
+	g.screen.Clear()
+	g.draw()
+}
+
+// statsLines returns the figures shown above the charts of the statistics
+// screen.
+func (g *game) statsLines() []string {
+	s := &g.stats
+	lo, hi := bounds(g.populations)
+	blo, bhi := bounds(s.births)
+	dlo, dhi := bounds(s.deaths)
+	var last time.Duration
+	if len(s.elapsed) > 0 {
+		last = s.elapsed[len(s.elapsed)-1]
+	}
+	return []string{
+		fmt.Sprintf("Rule         %s", ruleLabel(g.life.Rule())),
+		fmt.Sprintf("Generation   %d", g.epoch),
+		fmt.Sprintf("Population   %d (%d-%d in the last %d generations)", g.life.Population(), lo, hi, len(g.populations)),
+		fmt.Sprintf("Births       %d (%d-%d)", lastValue(s.births), blo, bhi),
+		fmt.Sprintf("Deaths       %d (%d-%d)", lastValue(s.deaths), dlo, dhi),
+		fmt.Sprintf("Seed         %d", g.seed),
+		fmt.Sprintf("Engine       %dx%d torus, density %.2f", g.life.Width(), g.life.Height(), g.density),
+		fmt.Sprintf("Step time    %v (average %v)", last, average(s.elapsed)),
+		fmt.Sprintf("Speed        %.4g gen/s", float64(time.Second)/float64(g.interval)),
+	}
+}
+
+// drawStats draws the statistics screen in place of the board: the figures of
+// statsLines, and charts of the populations and of the births and deaths of
+// the last generations.
+func (g *game) drawStats(cols, rows int) {
+	style := g.theme.status
+	for y := 0; y < rows; y++ {
+		g.drawText(0, y, cols, g.theme.base, "")
+	}
+	lines := append([]string{"Statistics (press I to go back)", ""}, g.statsLines()...)
+	for i, l := range lines {
+		if i >= rows {
+			return
+		}
+		g.drawText(0, i, cols, g.theme.base, " "+l)
+	}
+	// Split the rows left between the three charts, each with a title.
+	h := (rows - len(lines) - 1) / 3
+	if h < 2 {
+		return
+	}
+	y := len(lines) + 1
+	charts := []struct {
+		title  string
+		values []uint
+	}{
+		{"Population", g.populations},
+		{"Births", g.stats.births},
+		{"Deaths", g.stats.deaths},
+	}
+	for _, c := range charts {
+		lo, hi := bounds(c.values)
+		g.drawText(0, y, cols, style, fmt.Sprintf(" %s %d-%d", c.title, lo, hi))
+		for row := y + 1; row < y+h; row++ {
+			g.drawText(0, row, cols, style, "")
+		}
+		g.drawBars(0, y+h-1, cols, h-1, c.values)
+		y += h
+	}
+}
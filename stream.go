@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// statsStream writes the statistics of every generation as JSON objects, one
+// per line, for other tools to analyze them.
+type statsStream struct {
+	out io.WriteCloser
+	enc *json.Encoder
+	// err is the first error writing the statistics, after which they are
+	// no longer written. Close returns it.
+	err error
+}
+
+// generationStats is the JSON object of a generation.
+type generationStats struct {
+	Epoch      uint `json:"epoch"`
+	Population uint `json:"population"`
+	Births     uint `json:"births"`
+	Deaths     uint `json:"deaths"`
+	// BoundingBox holds the region of the live cells, or null if there are
+	// none.
+	BoundingBox *boundingBox `json:"bounding_box"`
+	// Hash is the hash of the board in hexadecimal, the same for the same
+	// boards.
+	Hash string `json:"hash"`
+}
+
+type boundingBox struct {
+	X uint `json:"x"`
+	Y uint `json:"y"`
+	W uint `json:"w"`
+	H uint `json:"h"`
+}
+
+// openStatsStream creates the file at path to write the statistics, or takes
+// the standard output if path is -.
+func openStatsStream(path string) (*statsStream, error) {
+	var out io.WriteCloser = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		out = f
+	}
+	return &statsStream{out: out, enc: json.NewEncoder(out)}, nil
+}
+
+// write writes the statistics of the last generation of l, with the changes
+// of its step. It is called by the steps, so it keeps its error for Close.
+func (s *statsStream) write(l *life.Life, epoch uint, st life.StepStats) {
+	if s.err != nil {
+		return
+	}
+	gs := generationStats{
+		Epoch:      epoch,
+		Population: st.Population,
+		Births:     st.Births,
+		Deaths:     st.Deaths,
+		Hash:       fmt.Sprintf("%016x", l.Field().Hash()),
+	}
+	if r, ok := l.BoundingBox(); ok {
+		gs.BoundingBox = &boundingBox{X: r.X, Y: r.Y, W: r.W, H: r.H}
+	}
+	if err := s.enc.Encode(gs); err != nil {
+		s.err = fmt.Errorf("stats: %w", err)
+	}
+}
+
+// Close closes the file of the statistics, leaving the standard output open,
+// and returns the first error writing them, if any.
+func (s *statsStream) Close() error {
+	err := s.err
+	if s.out != os.Stdout {
+		if cerr := s.out.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
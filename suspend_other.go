@@ -0,0 +1,15 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package main
+
+import "os"
+
+// notifyJobControl relays the signals of the shell job control: SIGTSTP to
+// suspend, and SIGCONT to resume after being stopped.
+func notifyJobControl(suspend, resume chan<- os.Signal) {}
+
+// stop stops the process until the shell continues it, reporting whether it
+// could.
+func stop() bool {
+	return false
+}
@@ -0,0 +1,23 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// notifyJobControl relays the signals of the shell job control: SIGTSTP to
+// suspend, and SIGCONT to resume after being stopped.
+func notifyJobControl(suspend, resume chan<- os.Signal) {
+	signal.Notify(suspend, unix.SIGTSTP)
+	signal.Notify(resume, unix.SIGCONT)
+}
+
+// stop stops the process until the shell continues it, reporting whether it
+// could.
+func stop() bool {
+	return unix.Kill(unix.Getpid(), unix.SIGSTOP) == nil
+}
@@ -0,0 +1,159 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// runFrontend runs the game on the terminal.
+func runFrontend(opts options) {
+	runTerminal(opts)
+}
+
+// runTerminal runs the game on the terminal until the user quits. The engine,
+// the patterns and the flags don't depend on it, so other frontends can drive
+// them in its place.
+func runTerminal(opts options) {
+	// farewell is written once the terminal is restored, telling why the game
+	// stopped on its own.
+	var farewell string
+	defer func() {
+		if farewell != "" {
+			fmt.Fprintln(os.Stderr, farewell)
+		}
+	}()
+
+	// Initialize screen
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		panic(err)
+	}
+	if err := screen.Init(); err != nil {
+		panic(err)
+	}
+	defer screen.Fini()
+	screen.EnableMouse()
+	screen.DisablePaste()
+	screen.HideCursor()
+	screen.Clear()
+
+	g := newGame(screen, opts)
+	g.out = os.Stdout
+	if err := g.openStream(opts.statsJSON); err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := g.closeStream(); err != nil {
+			farewell = err.Error()
+		}
+	}()
+	g.frame = time.NewTicker(time.Second / time.Duration(opts.fps))
+	if opts.record != "" {
+		g.recorder = startRecording(opts.record, flagSet, g)
+		defer func() {
+			if err := g.recorder.Close(); err != nil {
+				farewell = err.Error()
+			}
+		}()
+	}
+
+	// The events of the terminal, the signals and the frames are handled
+	// between the steps of the board, one at a time.
+	ctx, quit := context.WithCancel(context.Background())
+	defer quit()
+	do := make(chan func())
+	go func() {
+		for {
+			event := screen.PollEvent()
+			do <- func() {
+				if g.event(event) {
+					quit()
+				}
+			}
+		}
+	}()
+	suspend, resume := make(chan os.Signal, 1), make(chan os.Signal, 1)
+	notifyJobControl(suspend, resume)
+	go func() {
+		for {
+			select {
+			case <-suspend:
+				do <- g.suspend
+			case <-resume:
+				// Stopped by someone else, so the terminal may have
+				// changed.
+				do <- func() {
+					g.screen.Sync()
+					g.resize()
+				}
+			case <-g.frame.C:
+				do <- func() {
+					if g.demo != nil && g.playDemo() {
+						quit()
+					} else if g.dirty {
+						g.draw()
+					}
+				}
+			}
+		}
+	}()
+
+	g.life.Run(ctx, life.RunOptions{
+		Tick: g.tick.C,
+		Step: func() {
+			if g.replay != nil && g.playReplay() {
+				return
+			}
+			if g.paused && g.replay == nil {
+				return
+			}
+			g.step()
+		},
+		Until: func(*life.Life) bool {
+			if !g.halted() {
+				return false
+			}
+			farewell = g.message
+			exitCode = int(g.outcome)
+			return true
+		},
+		Do: do,
+	})
+	if g.renderer.graphics() {
+		g.clearImages()
+	}
+}
+
+// event handles an event of the terminal and reports whether the program must
+// exit.
+func (g *game) event(event tcell.Event) bool {
+	switch event := event.(type) {
+	case *tcell.EventResize:
+		g.resize()
+	case *tcell.EventKey:
+		if g.replay != nil {
+			// The user only watches the replay.
+			return event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC
+		}
+		if g.recorder != nil {
+			g.recorder.record(g.epoch, event)
+		}
+		return g.key(event)
+	case *tcell.EventMouse:
+		if g.replay != nil {
+			return false
+		}
+		if g.recorder != nil {
+			g.recorder.record(g.epoch, event)
+		}
+		g.mouse(event)
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// theme holds the colors of the user interface.
+type theme struct {
+	name string
+	// base is the style of the board.
+	base tcell.Style
+	// age colors the live cells from newborn to old.
+	age gradient
+	// heat shades the background from sparse or quiet regions to dense or
+	// active ones.
+	heat gradient
+	// status is the style of the status bar.
+	status tcell.Style
+	// ghost is the color of the stamp preview.
+	ghost tcell.Color
+	// cursor is the style of the cursor of the edit mode.
+	cursor tcell.Style
+	// selection is the background color of the selection.
+	selection tcell.Color
+	// grid is the background color of the grid overlay.
+	grid tcell.Color
+	// birth and death are the colors of the cells changing in the preview of
+	// the next generation.
+	birth, death tcell.Color
+	// states holds the colors of the cell states of multi-state rules.
+	states []tcell.Color
+}
+
+func rgb(r, g, b int32) tcell.Color {
+	return tcell.NewRGBColor(r, g, b)
+}
+
+// themes holds the built-in themes followed by the ones in the configuration
+// file.
+var themes = []*theme{
+	{
+		name:      "default",
+		base:      tcell.StyleDefault.Background(tcell.ColorReset).Foreground(tcell.ColorReset),
+		age:       gradient{rgb(255, 255, 170), rgb(255, 160, 0), rgb(200, 40, 40), rgb(90, 40, 140)},
+		heat:      gradient{rgb(0, 0, 60), rgb(0, 40, 140), rgb(140, 0, 70), rgb(200, 40, 0)},
+		status:    tcell.StyleDefault.Reverse(true),
+		ghost:     tcell.ColorYellow,
+		cursor:    tcell.StyleDefault.Reverse(true),
+		selection: tcell.ColorNavy,
+		grid:      rgb(50, 50, 50),
+		birth:     tcell.ColorGreen,
+		death:     tcell.ColorRed,
+		states:    []tcell.Color{tcell.ColorYellow, tcell.ColorRed, tcell.ColorBlue, tcell.ColorGreen},
+	},
+	{
+		name:      "matrix",
+		base:      tcell.StyleDefault.Foreground(rgb(0, 255, 70)).Background(tcell.ColorBlack),
+		age:       gradient{rgb(200, 255, 200), rgb(0, 255, 70), rgb(0, 90, 20)},
+		heat:      gradient{rgb(0, 20, 0), rgb(0, 60, 10), rgb(0, 110, 30)},
+		status:    tcell.StyleDefault.Foreground(rgb(200, 255, 200)).Background(rgb(0, 80, 0)),
+		ghost:     tcell.ColorWhite,
+		cursor:    tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(rgb(0, 255, 70)),
+		selection: rgb(0, 60, 0),
+		grid:      rgb(0, 35, 0),
+		birth:     tcell.ColorWhite,
+		death:     rgb(0, 90, 20),
+		states:    []tcell.Color{rgb(0, 255, 70), rgb(0, 170, 50), rgb(0, 90, 20)},
+	},
+	{
+		name:      "amber",
+		base:      tcell.StyleDefault.Foreground(rgb(255, 176, 0)).Background(tcell.ColorBlack),
+		age:       gradient{rgb(255, 230, 150), rgb(255, 176, 0), rgb(120, 60, 0)},
+		heat:      gradient{rgb(30, 15, 0), rgb(80, 40, 0), rgb(140, 70, 0)},
+		status:    tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(rgb(255, 176, 0)),
+		ghost:     tcell.ColorWhite,
+		cursor:    tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(rgb(255, 176, 0)),
+		selection: rgb(80, 40, 0),
+		grid:      rgb(45, 25, 0),
+		birth:     tcell.ColorWhite,
+		death:     rgb(120, 60, 0),
+		states:    []tcell.Color{rgb(255, 176, 0), rgb(190, 110, 0), rgb(120, 60, 0)},
+	},
+	{
+		name:      "paper",
+		base:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite),
+		age:       gradient{rgb(220, 0, 0), rgb(0, 0, 0), rgb(150, 150, 150)},
+		heat:      gradient{rgb(220, 230, 255), rgb(255, 220, 180), rgb(255, 170, 150)},
+		status:    tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		ghost:     tcell.ColorBlue,
+		cursor:    tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		selection: rgb(200, 220, 255),
+		grid:      rgb(225, 225, 225),
+		birth:     tcell.ColorGreen,
+		death:     tcell.ColorRed,
+		states:    []tcell.Color{tcell.ColorBlack, tcell.ColorRed, tcell.ColorBlue},
+	},
+	{
+		name:      "ocean",
+		base:      tcell.StyleDefault.Foreground(rgb(120, 200, 255)).Background(rgb(0, 20, 40)),
+		age:       gradient{rgb(255, 255, 255), rgb(0, 200, 255), rgb(0, 60, 140)},
+		heat:      gradient{rgb(0, 30, 60), rgb(0, 60, 110), rgb(0, 100, 150)},
+		status:    tcell.StyleDefault.Foreground(rgb(0, 20, 40)).Background(rgb(120, 200, 255)),
+		ghost:     tcell.ColorYellow,
+		cursor:    tcell.StyleDefault.Foreground(rgb(0, 20, 40)).Background(rgb(255, 255, 255)),
+		selection: rgb(0, 60, 100),
+		grid:      rgb(0, 40, 65),
+		birth:     tcell.ColorWhite,
+		death:     rgb(0, 60, 140),
+		states:    []tcell.Color{rgb(120, 200, 255), rgb(0, 140, 200), rgb(0, 60, 140)},
+	},
+}
+
+// findTheme returns the theme with the given name.
+func findTheme(name string) (*theme, error) {
+	for _, t := range themes {
+		if t.name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown theme: %s", name)
+}
+
+// loadThemes adds the themes of the [theme.NAME] tables of the configuration.
+// They start from the default theme and replace the given colors. A theme with
+// the name of an existing one replaces it.
+func loadThemes(c config) error {
+	for _, name := range c.tables("theme") {
+		t, err := parseTheme(c, name)
+		if err != nil {
+			return err
+		}
+		replaced := false
+		for i, old := range themes {
+			if old.name == name {
+				themes[i], replaced = t, true
+			}
+		}
+		if !replaced {
+			themes = append(themes, t)
+		}
+	}
+	return nil
+}
+
+func parseTheme(c config, name string) (*theme, error) {
+	table := "theme." + name
+	t := *themes[0]
+	t.name = name
+	color := func(key string) (tcell.Color, bool, error) {
+		s, ok, err := c.String(table, key)
+		if !ok || err != nil {
+			return tcell.ColorDefault, false, err
+		}
+		if col := tcell.GetColor(s); col != tcell.ColorDefault || s == "default" {
+			return col, true, nil
+		}
+		return tcell.ColorDefault, false, fmt.Errorf("%s.%s: invalid color: %s", table, key, s)
+	}
+	colors := func(key string) ([]tcell.Color, bool, error) {
+		names, ok, err := c.Strings(table, key)
+		if !ok || err != nil {
+			return nil, false, err
+		}
+		var result []tcell.Color
+		for _, s := range names {
+			col := tcell.GetColor(s)
+			if col == tcell.ColorDefault {
+				return nil, false, fmt.Errorf("%s.%s: invalid color: %s", table, key, s)
+			}
+			result = append(result, col)
+		}
+		if len(result) == 0 {
+			return nil, false, fmt.Errorf("%s.%s: needs at least one color", table, key)
+		}
+		return result, true, nil
+	}
+
+	var err error
+	set := func(key string, apply func(tcell.Color)) {
+		if err != nil {
+			return
+		}
+		var col tcell.Color
+		var ok bool
+		if col, ok, err = color(key); ok {
+			apply(col)
+		}
+	}
+	set("foreground", func(col tcell.Color) { t.base = t.base.Foreground(col) })
+	set("background", func(col tcell.Color) { t.base = t.base.Background(col) })
+	set("status_foreground", func(col tcell.Color) { t.status = t.status.Reverse(false).Foreground(col) })
+	set("status_background", func(col tcell.Color) { t.status = t.status.Reverse(false).Background(col) })
+	set("ghost", func(col tcell.Color) { t.ghost = col })
+	set("cursor_foreground", func(col tcell.Color) { t.cursor = t.cursor.Reverse(false).Foreground(col) })
+	set("cursor_background", func(col tcell.Color) { t.cursor = t.cursor.Reverse(false).Background(col) })
+	set("selection", func(col tcell.Color) { t.selection = col })
+	set("grid", func(col tcell.Color) { t.grid = col })
+	set("birth", func(col tcell.Color) { t.birth = col })
+	set("death", func(col tcell.Color) { t.death = col })
+	if err != nil {
+		return nil, err
+	}
+	if age, ok, err := colors("age"); err != nil {
+		return nil, err
+	} else if ok {
+		t.age = age
+	}
+	if heat, ok, err := colors("heat"); err != nil {
+		return nil, err
+	} else if ok {
+		t.heat = heat
+	}
+	if states, ok, err := colors("states"); err != nil {
+		return nil, err
+	} else if ok {
+		t.states = states
+	}
+	return &t, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// showTimeline reports whether the timeline is drawn: on pause, when there are
+// generations to go back or forth to.
+func (g *game) showTimeline() bool {
+	return g.paused && g.rewind.n+len(g.forward) > 0
+}
+
+// timeline returns the number of generations of the timeline and the position
+// of the current one.
+func (g *game) timeline() (total, current int) {
+	return g.rewind.n + 1 + len(g.forward), g.rewind.n
+}
+
+// drawTimeline draws the timeline on the last row of the board, like the one
+// of a video player, with a thumb on the current generation.
+func (g *game) drawTimeline(cols, rows int) {
+	total, current := g.timeline()
+	row := rows - 1
+	thumb := current * cols / total
+	for x := 0; x < cols; x++ {
+		r := '─'
+		if x == thumb {
+			r = '●'
+		}
+		g.screen.SetContent(x, row, r, nil, g.theme.status)
+	}
+	label := fmt.Sprintf(" Gen %d (%d/%d) ", g.epoch, current+1, total)
+	x := thumb + 2
+	if x+len(label) > cols {
+		x = thumb - 1 - len(label)
+	}
+	if x >= 0 {
+		g.drawText(x, row, x+len(label), g.theme.status, label)
+	}
+}
+
+// scrub handles the clicks and drags on the timeline, going to the generation
+// under the mouse pointer, and reports whether the event was consumed.
+func (g *game) scrub(button tcell.ButtonMask) bool {
+	cols, rows := g.boardSize()
+	if !g.showTimeline() || g.mouseY != rows-1 {
+		return false
+	}
+	if button == tcell.Button1 {
+		total, _ := g.timeline()
+		g.seek(g.mouseX * total / cols)
+		g.draw()
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// maxTrail is the longest decay trail, in generations.
+const maxTrail = 100
+
+// trail counts the generations since every cell died, to draw the cells that
+// died recently fading out, like the phosphor of old screens. The trails of
+// the spaceships show where they come from.
+type trail struct {
+	w, h uint
+	// length is the number of generations the dead cells take to fade out.
+	length uint
+	// since holds the generations since every cell died, or 0 if it is alive
+	// or faded out.
+	since [][]uint
+}
+
+func newTrail(w, h, length uint) *trail {
+	return &trail{w: w, h: h, length: length, since: newCounts(w, h)}
+}
+
+// add ages the trails with the last generation of l. The trails start again
+// when the board changes size.
+func (t *trail) add(l *life.Life) {
+	if t.w != l.Width() || t.h != l.Height() {
+		*t = *newTrail(l.Width(), l.Height(), t.length)
+	}
+	for y := uint(0); y < l.Height(); y++ {
+		for x := uint(0); x < l.Width(); x++ {
+			switch s := &t.since[y][x]; {
+			case l.Field().Alive(int(x), int(y)):
+				*s = 0
+			case l.Previous().Alive(int(x), int(y)):
+				*s = 1
+			case *s > 0:
+				if *s++; *s > t.length {
+					*s = 0
+				}
+			}
+		}
+	}
+}
+
+// setTrail draws the cells dead in the last length generations, or stops it
+// with a length of 0.
+func (g *game) setTrail(length uint) error {
+	switch {
+	case length > maxTrail:
+		return fmt.Errorf("trail must be up to %d generations", maxTrail)
+	case length == 0:
+		g.trail = nil
+	case g.mono:
+		return errors.New("no colors in monochrome mode")
+	default:
+		g.trail = newTrail(g.life.Width(), g.life.Height(), length)
+	}
+	g.draw()
+	return nil
+}
+
+// fading returns the smallest number of generations since a cell of the
+// region of the viewport died, or 0 if there is no trail on it.
+func (g *game) fading(x0, y0, x1, y1 int) uint {
+	t := g.trail
+	if t == nil || t.w != g.life.Width() || t.h != g.life.Height() {
+		return 0
+	}
+	min := uint(0)
+	for y := y0; y < y1 && y < int(g.life.Height()); y++ {
+		for x := x0; x < x1 && x < int(g.life.Width()); x++ {
+			p := g.toBoard(x, y)
+			if s := t.since[p.Y][p.X]; s > 0 && (min == 0 || s < min) {
+				min = s
+			}
+		}
+	}
+	return min
+}
+
+// trailStyle returns the style of the trail of a cell dead since the given
+// number of generations, going from the color of the deaths to the background.
+func (g *game) trailStyle(since uint) tcell.Style {
+	bg := toRGBA(g.theme.base, false)
+	gr := gradient{g.theme.death, rgb(int32(bg.R), int32(bg.G), int32(bg.B))}
+	return g.theme.base.Foreground(gr.at(float64(since) / float64(g.trail.length+1)))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+)
+
+// verifyCases hold the reference hashes of testdata, written with:
+//
+//	go_life render -rule RULE -width W -height H -seed SEED -generations N -verify EVERY
+var verifyCases = []struct {
+	file          string
+	rule          string
+	width, height uint
+	seed          int64
+	generations   uint
+	every         uint
+}{
+	{"verify_b3s23_64x64_seed1.txt", "B3/S23", 64, 64, 1, 1000, 100},
+	{"verify_b36s23_48x32_seed7.txt", "B36/S23", 48, 32, 7, 500, 50},
+}
+
+func TestVerifyHashes(t *testing.T) {
+	for _, c := range verifyCases {
+		want, err := os.ReadFile(filepath.Join("testdata", c.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := life.ParseNamedRule(c.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := options{
+			rule:        r,
+			density:     0.5,
+			width:       c.width,
+			height:      c.height,
+			seed:        c.seed,
+			generations: c.generations,
+			verify:      c.every,
+		}
+		var got bytes.Buffer
+		if err := runHeadless(opts, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("%s: the hashes changed:\n%s", c.file, got.String())
+		}
+	}
+}
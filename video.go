@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// video holds the options of the videos written by render.
+type video struct {
+	// path is the video file, its format picked by ffmpeg from the extension.
+	path string
+	// w and h hold the size of the video in pixels, or zeros for -scale
+	// pixels per cell.
+	w, h int
+	fps  uint
+	// duration is the length of the video, or 0 for one frame per generation
+	// of -generations.
+	duration time.Duration
+}
+
+// parseVideoSize reads a size written as WIDTHxHEIGHT.
+func parseVideoSize(s string) (w, h int, err error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(s), "x")
+	w, errW := strconv.Atoi(ws)
+	h, errH := strconv.Atoi(hs)
+	if !ok || errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid video size, use WIDTHxHEIGHT: %s", s)
+	}
+	return w, h, nil
+}
+
+// newImageGame returns a game without a terminal drawing the whole board in
+// images, with cells of -scale pixels. The caller closes the stream of the
+// statistics with closeStream.
+func newImageGame(opts options) (*game, error) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+	screen.SetSize(ansiCols, ansiRows)
+	g := newGame(screen, opts)
+	g.zoom = int(opts.scale)
+	return g, g.openStream(opts.statsJSON)
+}
+
+// image returns the whole board drawn by a game of newImageGame.
+func (g *game) image() *image.RGBA {
+	return g.boardImage(int(g.life.Width())*g.zoom*g.dotWidth, int(g.life.Height())*g.zoom, 1, 1, nil)
+}
+
+// runVideo runs the game without a terminal, drawing every generation with
+// cells of -scale pixels and piping the frames as raw RGB to ffmpeg, which
+// scales them to the size of the video and encodes them.
+func runVideo(opts options) (err error) {
+	v := opts.video
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("the videos are encoded by ffmpeg, install it: %w", err)
+	}
+	g, err := newImageGame(opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := g.closeStream(); err == nil {
+			err = cerr
+		}
+	}()
+	w, h := int(g.life.Width())*g.zoom*g.dotWidth, int(g.life.Height())*g.zoom
+
+	// The encoders of yuv420p take even sizes only.
+	filter := "pad=ceil(iw/2)*2:ceil(ih/2)*2"
+	if v.w > 0 {
+		filter = fmt.Sprintf("scale=%d:%d:flags=neighbor,%s", v.w, v.h, filter)
+	}
+	cmd := exec.Command(ffmpeg, "-loglevel", "error", "-y",
+		"-f", "rawvideo", "-pix_fmt", "rgb24", "-s", fmt.Sprintf("%dx%d", w, h), "-r", strconv.Itoa(int(v.fps)), "-i", "-",
+		"-vf", filter, "-pix_fmt", "yuv420p", v.path)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	frames := opts.generations + 1
+	if v.duration > 0 {
+		frames = uint(v.duration.Seconds() * float64(v.fps))
+	}
+	start := time.Now()
+	rgb := make([]byte, w*h*3)
+	for i := uint(0); i < frames; i++ {
+		if i > 0 {
+			g.step()
+			if g.halted() || g.paused {
+				fmt.Fprintln(os.Stderr, g.message)
+				exitCode = int(g.outcome)
+				break
+			}
+		}
+		img := g.image()
+		for p, q := 0, 0; p < len(img.Pix); p, q = p+4, q+3 {
+			copy(rgb[q:q+3], img.Pix[p:p+3])
+		}
+		if _, err := in.Write(rgb); err != nil {
+			// ffmpeg tells why it stopped reading.
+			break
+		}
+	}
+	in.Close()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg failed writing %s: %w", v.path, err)
+	}
+	logs.Info("video done", "path", v.path, "generations", g.epoch, "elapsed", time.Since(start))
+	return nil
+}
@@ -0,0 +1,197 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"math/rand"
+	"syscall/js"
+	"time"
+
+	"github.com/kerrigan29a/go_life/pkg/life"
+	"github.com/kerrigan29a/go_life/pkg/render"
+)
+
+// canvasID is the id of the canvas element the board is drawn on.
+const canvasID = "life"
+
+// webCellSize is the size in CSS pixels of the cells when the board fits the
+// canvas.
+const webCellSize = 4
+
+// web draws the game on an HTML canvas, with one pixel per cell scaled up by
+// the page.
+type web struct {
+	life     *life.Life
+	theme    *theme
+	density  float64
+	rng      *rand.Rand
+	paused   bool
+	interval time.Duration
+	tick     *time.Ticker
+	canvas   js.Value
+	ctx      js.Value
+	// buf holds the RGBA pixels of the board, copied from the frame on
+	// every draw.
+	buf js.Value
+	// button is the mouse button held down on the canvas, or -1.
+	button int
+}
+
+// runFrontend runs the game on the canvas of the page. It never returns, since
+// the page keeps calling the event handlers.
+func runFrontend(opts options) {
+	doc := js.Global().Get("document")
+	canvas := doc.Call("getElementById", canvasID)
+	if canvas.IsNull() {
+		panic("no canvas with id " + canvasID)
+	}
+	w, h := opts.width, opts.height
+	if w == 0 {
+		w = uint(canvas.Get("clientWidth").Int() / webCellSize)
+	}
+	if h == 0 {
+		h = uint(canvas.Get("clientHeight").Int() / webCellSize)
+	}
+	if w == 0 || h == 0 {
+		w, h = minCols, minRows
+	}
+	canvas.Set("width", w)
+	canvas.Set("height", h)
+
+	t := opts.theme
+	if opts.palette != nil {
+		t = opts.palette.apply(t)
+	}
+	wb := &web{
+		life:     newLife(opts.rule, w, h),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		theme:    t,
+		density:  opts.density,
+		paused:   opts.paused,
+		interval: time.Second / time.Duration(opts.gps),
+		canvas:   canvas,
+		ctx:      canvas.Call("getContext", "2d"),
+		buf:      js.Global().Get("Uint8ClampedArray").New(int(w * h * 4)),
+		button:   -1,
+	}
+	wb.life.Field().Randomize(wb.rng, opts.density)
+	wb.listen(doc)
+	wb.draw()
+
+	wb.tick = time.NewTicker(wb.interval)
+	for range wb.tick.C {
+		if wb.paused {
+			continue
+		}
+		wb.life.Step()
+		wb.draw()
+	}
+}
+
+// listen maps the keys and the mouse of the page to the game. The keys are the
+// ones of the terminal, and the mouse buttons draw like in the terminal: the
+// main one turns the cells on and any other one turns them off.
+func (wb *web) listen(doc js.Value) {
+	doc.Call("addEventListener", "keydown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		e := args[0]
+		if e.Get("ctrlKey").Bool() || e.Get("metaKey").Bool() || e.Get("altKey").Bool() {
+			return nil
+		}
+		if wb.key(e.Get("key").String()) {
+			e.Call("preventDefault")
+		}
+		return nil
+	}))
+	wb.canvas.Call("addEventListener", "contextmenu", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		args[0].Call("preventDefault")
+		return nil
+	}))
+	wb.canvas.Call("addEventListener", "mousedown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		wb.button = args[0].Get("button").Int()
+		wb.mouse(args[0])
+		return nil
+	}))
+	wb.canvas.Call("addEventListener", "mousemove", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if wb.button >= 0 {
+			wb.mouse(args[0])
+		}
+		return nil
+	}))
+	js.Global().Call("addEventListener", "mouseup", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		wb.button = -1
+		return nil
+	}))
+}
+
+// key handles a key of the page and reports whether it was used.
+func (wb *web) key(k string) bool {
+	switch k {
+	case "p", "P", " ":
+		wb.paused = !wb.paused
+	case "n", "N":
+		if !wb.paused {
+			return true
+		}
+		wb.life.Step()
+	case "x", "Delete":
+		wb.life.Field().Clear()
+	case "R":
+		wb.life.Field().Randomize(wb.rng, wb.density)
+	case "D":
+		wb.density = nextDensity(wb.density)
+		wb.life.Field().Randomize(wb.rng, wb.density)
+	case "+":
+		wb.setInterval(wb.interval / 2)
+	case "-":
+		wb.setInterval(wb.interval * 2)
+	case "T":
+		for i, t := range themes {
+			if t.name == wb.theme.name {
+				wb.theme = themes[(i+1)%len(themes)]
+				break
+			}
+		}
+	default:
+		return false
+	}
+	wb.draw()
+	return true
+}
+
+// setInterval changes the time between generations, within the limits of the
+// terminal.
+func (wb *web) setInterval(d time.Duration) {
+	if d < minInterval {
+		d = minInterval
+	}
+	if d > maxInterval {
+		d = maxInterval
+	}
+	wb.interval = d
+	wb.tick.Reset(d)
+}
+
+// mouse turns the cell under the mouse pointer on or off.
+func (wb *web) mouse(e js.Value) {
+	w, h := int(wb.life.Width()), int(wb.life.Height())
+	cw, ch := wb.canvas.Get("clientWidth").Int(), wb.canvas.Get("clientHeight").Int()
+	if cw == 0 || ch == 0 {
+		return
+	}
+	x := e.Get("offsetX").Int() * w / cw
+	y := e.Get("offsetY").Int() * h / ch
+	// The edges of the canvas map past the last row and column.
+	if err := wb.life.Field().SetChecked(x, y, wb.button == 0); err != nil {
+		return
+	}
+	wb.draw()
+}
+
+// draw copies the board to the canvas.
+func (wb *web) draw() {
+	r := render.Image{Scale: 1, Alive: toRGBA(wb.theme.base, true), Dead: toRGBA(wb.theme.base, false)}
+	frame := r.Render(wb.life, render.Full(wb.life))
+	js.CopyBytesToJS(wb.buf, frame.Image.Pix)
+	img := js.Global().Get("ImageData").New(wb.buf, int(wb.life.Width()), int(wb.life.Height()))
+	wb.ctx.Call("putImageData", img, 0, 0)
+}
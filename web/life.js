@@ -0,0 +1,12 @@
+// Runs go_life.wasm on the canvas of the page. The query parameters of the
+// page are passed as flags, so index.html?bs=B36/S23&gps=20 runs HighLife at 20
+// generations per second.
+(async () => {
+	const go = new Go();
+	go.argv = ["go_life"];
+	for (const [name, value] of new URLSearchParams(location.search)) {
+		go.argv.push(value === "" ? `-${name}` : `-${name}=${value}`);
+	}
+	const result = await WebAssembly.instantiateStreaming(fetch("go_life.wasm"), go.importObject);
+	await go.run(result.instance);
+})();
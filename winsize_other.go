@@ -0,0 +1,9 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package main
+
+// cellPixels returns the size in pixels of the characters of the terminal, or
+// zeros if unknown.
+func cellPixels() (w, h int) {
+	return 0, 0
+}